@@ -21,6 +21,7 @@ import (
 type Group[T any] struct {
 	cancelOnErr bool
 	timeout     time.Duration
+	onTaskStart func(i int)
 
 	semCh chan struct{}                      // max goroutines
 	funcs []func(context.Context) (T, error) // queued operations
@@ -69,6 +70,18 @@ func (g *Group[T]) SetTimeout(d time.Duration) {
 	g.timeout = d
 }
 
+// SetOnTaskStart sets a callback that is invoked when each queued function's
+// goroutine begins running. i is the index of the function in queue order.
+//
+// This is intended as a testing and debugging aid, it allows tests to
+// deterministically coordinate with the Group's goroutines without sleeps.
+// By default no callback is set.
+func (g *Group[T]) SetOnTaskStart(fn func(i int)) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.onTaskStart = fn
+}
+
 // Queue queues a function to be run in a goroutine.
 // Once all desired functions have been queued, execute them by calling Wait.
 func (g *Group[T]) Queue(f func(context.Context) (T, error)) {
@@ -77,6 +90,14 @@ func (g *Group[T]) Queue(f func(context.Context) (T, error)) {
 	g.funcs = append(g.funcs, f)
 }
 
+// Len returns the number of functions that are currently queued.
+// This can be useful for setting up progress reporting before calling Wait.
+func (g *Group[T]) Len() int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return len(g.funcs)
+}
+
 // Wait executes all the queued functions, each of them in their own goroutines, and waits
 // for them to complete. It then returns a list of results and any errors that occurred.
 //
@@ -86,7 +107,8 @@ func (g *Group[T]) Queue(f func(context.Context) (T, error)) {
 // If the Group was configured to cancel on the first error, if a goroutine errors all others
 // will be cancelled and the returned error will be the first error that occurred.
 // Otherwise, all goroutines will run to completion, and the returned error will be an
-// errors.List containing each error. The errors will not be in any particular order.
+// errors.List containing each error. The errors are ordered by the order in which
+// calls to Queue were made, not by completion order, so output is deterministic.
 func (g *Group[T]) Wait(ctx context.Context) ([]T, error) {
 	// Ensure that the Group is not modified while running.
 	// If anything tries to modify the Group it will be blocked until Wait completes.
@@ -114,6 +136,33 @@ func (g *Group[T]) Wait(ctx context.Context) ([]T, error) {
 	return vs, nil
 }
 
+// WaitContext is like Wait but returns ctx.Err() as soon as ctx is done,
+// without waiting for the remaining goroutines to observe the cancellation.
+// This bounds how long WaitContext can block even if a queued function does
+// not honour its context promptly.
+//
+// Note that when WaitContext returns early the still-running goroutines are
+// detached, not killed. They continue running in the background until they
+// return on their own, and their results are discarded. The Group remains
+// locked until they finish, so it cannot be reused before then.
+func (g *Group[T]) WaitContext(ctx context.Context) ([]T, error) {
+	type waitResult struct {
+		vs  []T
+		err error
+	}
+	ch := make(chan waitResult, 1)
+	go func() {
+		vs, err := g.Wait(ctx)
+		ch <- waitResult{vs, err}
+	}()
+	select {
+	case r := <-ch:
+		return r.vs, r.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
 // WaitLax is similar to Wait but returns a slice of Result values containing the returned
 // value and error, if any, from each goroutine. This can be useful if you wish to get a list
 // of partial results and errors associated with each goroutine.
@@ -170,6 +219,9 @@ func (g *Group[T]) wait(ctx context.Context, lax bool) (results []Result[T], fir
 					<-g.semCh
 				}
 			}()
+			if g.onTaskStart != nil {
+				g.onTaskStart(i)
+			}
 			v, err := f(runCtx)
 			resCh <- Result[T]{v, err, i}
 		}(i, f)
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"reflect"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -64,6 +65,29 @@ func TestGroupOrdered(t *testing.T) {
 	}
 }
 
+func TestGroupLen(t *testing.T) {
+	var g async.Group[int]
+	if g.Len() != 0 {
+		t.Errorf("got len %d, want 0", g.Len())
+	}
+	for i := 0; i < 5; i++ {
+		i := i
+		g.Queue(func(ctx context.Context) (int, error) {
+			return i, nil
+		})
+	}
+	if g.Len() != 5 {
+		t.Errorf("got len %d, want 5", g.Len())
+	}
+	if _, err := g.Wait(context.Background()); err != nil {
+		t.Fatalf("want nil error, got %v", err)
+	}
+	// The queue is cleared after Wait so the Group can be reused.
+	if g.Len() != 0 {
+		t.Errorf("got len %d after Wait, want 0", g.Len())
+	}
+}
+
 func TestGroupMultipleErrors(t *testing.T) {
 	var g async.Group[int]
 	for i := 0; i < 5; i++ {
@@ -211,3 +235,110 @@ func TestGroupWaitLax(t *testing.T) {
 		}
 	}
 }
+
+func TestGroupOnTaskStart(t *testing.T) {
+	var g async.Group[int]
+	g.SetCancelOnError(true)
+
+	var mu sync.Mutex
+	started := make(map[int]bool)
+	g.SetOnTaskStart(func(i int) {
+		mu.Lock()
+		defer mu.Unlock()
+		started[i] = true
+	})
+
+	firstErr := fmt.Errorf("boom")
+	g.Queue(func(ctx context.Context) (int, error) {
+		return -1, firstErr
+	})
+	for i := 1; i < 4; i++ {
+		i := i
+		g.Queue(func(ctx context.Context) (int, error) {
+			// Block until cancellation. If the cancellation never propagates
+			// Wait would hang, so Wait returning proves it reached this task.
+			<-ctx.Done()
+			return i, ctx.Err()
+		})
+	}
+	_, err := g.Wait(context.Background())
+	if err != firstErr {
+		t.Errorf("got %v, want %v", err, firstErr)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(started) != 4 {
+		t.Errorf("got %d started tasks, want 4", len(started))
+	}
+	for i := 0; i < 4; i++ {
+		if !started[i] {
+			t.Errorf("want task %d to have started, but didn't", i)
+		}
+	}
+}
+
+func TestGroupErrorOrder(t *testing.T) {
+	var g async.Group[int]
+	// Queue tasks that complete in reverse order so completion order differs
+	// from queue order.
+	for i := 0; i < 4; i++ {
+		i := i
+		g.Queue(func(ctx context.Context) (int, error) {
+			time.Sleep(time.Duration(4-i) * 20 * time.Millisecond)
+			if i%2 == 0 {
+				return 0, fmt.Errorf("task %d failed", i)
+			}
+			return i, nil
+		})
+	}
+	_, err := g.Wait(context.Background())
+	var errs errors.List
+	if !errors.As(err, &errs) {
+		t.Fatalf("got %T, want errors.List", err)
+	}
+	want := []string{"task 0 failed", "task 2 failed"}
+	if len(errs) != len(want) {
+		t.Fatalf("got %d errors, want %d", len(errs), len(want))
+	}
+	for i, e := range errs {
+		if e.Error() != want[i] {
+			t.Errorf("got error %q at index %d, want %q", e.Error(), i, want[i])
+		}
+	}
+}
+
+func TestGroupWaitContext(t *testing.T) {
+	var g async.Group[int]
+	g.Queue(func(ctx context.Context) (int, error) {
+		// Deliberately ignore ctx to simulate a misbehaving task.
+		time.Sleep(2 * time.Second)
+		return 1, nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := g.WaitContext(ctx)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("WaitContext took %s, want it to return promptly after the context was done", elapsed)
+	}
+	if err != context.DeadlineExceeded {
+		t.Errorf("got error %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestGroupWaitContextCompletes(t *testing.T) {
+	var g async.Group[int]
+	g.Queue(func(ctx context.Context) (int, error) {
+		return 1, nil
+	})
+
+	vs, err := g.WaitContext(context.Background())
+	if err != nil {
+		t.Fatalf("want nil error, got %v", err)
+	}
+	if len(vs) != 1 || vs[0] != 1 {
+		t.Errorf("got %v, want [1]", vs)
+	}
+}
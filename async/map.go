@@ -0,0 +1,25 @@
+package async
+
+import "context"
+
+// MapN runs fn on each item in items, with at most concurrency goroutines
+// running at once. If concurrency is zero or negative, there is no limit.
+// The results are returned in the same order as items.
+//
+// All items are processed even if some fail. If any errors occurred, the
+// returned error is an errors.List containing each error in item order and
+// the result slice is nil. Use a Group directly if cancel-on-first-error
+// behaviour is needed.
+func MapN[In, Out any](ctx context.Context, items []In, concurrency int, fn func(ctx context.Context, item In) (Out, error)) ([]Out, error) {
+	var g Group[Out]
+	// MapN owns the Group for its entire lifetime so locking is unnecessary.
+	g.SetLocking(false)
+	g.SetMaxGoroutines(concurrency)
+	for _, item := range items {
+		item := item
+		g.Queue(func(ctx context.Context) (Out, error) {
+			return fn(ctx, item)
+		})
+	}
+	return g.Wait(ctx)
+}
@@ -0,0 +1,81 @@
+package async_test
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cszatmary/goutils/async"
+	"github.com/cszatmary/goutils/errors"
+)
+
+func TestMapN(t *testing.T) {
+	items := []int{1, 2, 3, 4, 5}
+	got, err := async.MapN(context.Background(), items, 2, func(ctx context.Context, item int) (string, error) {
+		// Sleep longer for earlier items so completion order differs from item order.
+		time.Sleep(time.Duration(len(items)-item) * 10 * time.Millisecond)
+		return fmt.Sprintf("item-%d", item), nil
+	})
+	if err != nil {
+		t.Fatalf("want nil error, got %v", err)
+	}
+	want := []string{"item-1", "item-2", "item-3", "item-4", "item-5"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestMapNConcurrencyCap(t *testing.T) {
+	const concurrency = 2
+	var mu sync.Mutex
+	active, maxActive := 0, 0
+	items := make([]int, 10)
+	_, err := async.MapN(context.Background(), items, concurrency, func(ctx context.Context, item int) (int, error) {
+		mu.Lock()
+		active++
+		if active > maxActive {
+			maxActive = active
+		}
+		mu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+		mu.Lock()
+		active--
+		mu.Unlock()
+		return 0, nil
+	})
+	if err != nil {
+		t.Fatalf("want nil error, got %v", err)
+	}
+	if maxActive > concurrency {
+		t.Errorf("got %d active goroutines, want at most %d", maxActive, concurrency)
+	}
+}
+
+func TestMapNErrors(t *testing.T) {
+	items := []int{1, 2, 3, 4}
+	got, err := async.MapN(context.Background(), items, 0, func(ctx context.Context, item int) (int, error) {
+		if item%2 == 0 {
+			return 0, fmt.Errorf("item %d failed", item)
+		}
+		return item, nil
+	})
+	if got != nil {
+		t.Errorf("got results %v, want nil", got)
+	}
+	var errs errors.List
+	if !errors.As(err, &errs) {
+		t.Fatalf("got %T, want errors.List", err)
+	}
+	want := []string{"item 2 failed", "item 4 failed"}
+	if len(errs) != len(want) {
+		t.Fatalf("got %d errors, want %d", len(errs), len(want))
+	}
+	for i, e := range errs {
+		if e.Error() != want[i] {
+			t.Errorf("got error %q at index %d, want %q", e.Error(), i, want[i])
+		}
+	}
+}
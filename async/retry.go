@@ -0,0 +1,72 @@
+package async
+
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+// RetryOptions is used to configure how Retry behaves.
+// All fields are optional.
+type RetryOptions struct {
+	// MaxAttempts is the total number of times fn will be run, including the
+	// first attempt. If the value is zero or negative, it defaults to 3.
+	MaxAttempts int
+	// BaseDelay is the delay before the first retry. The delay doubles after
+	// each failed attempt. If the value is zero or negative, it defaults to 100ms.
+	BaseDelay time.Duration
+	// MaxDelay caps the delay between attempts.
+	// If the value is zero or negative, there is no cap.
+	MaxDelay time.Duration
+	// Jitter randomizes each delay to a value between half and the full
+	// computed delay. This avoids thundering herds when many clients retry
+	// at the same cadence.
+	Jitter bool
+	// Retryable reports whether an error is transient and worth retrying.
+	// If it returns false, Retry returns the error immediately without
+	// further attempts. If nil, all errors are considered retryable.
+	Retryable func(err error) bool
+}
+
+// Retry runs fn until it succeeds, the configured number of attempts is
+// exhausted, the error is not retryable, or ctx is done. It returns nil on
+// success, otherwise the last error from fn (or the context error if ctx
+// became done while waiting between attempts).
+//
+// Retry is the single place for retry/backoff policy so callers don't each
+// hand-roll their own loops.
+func Retry(ctx context.Context, opts RetryOptions, fn func(ctx context.Context) error) error {
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = 3
+	}
+	if opts.BaseDelay <= 0 {
+		opts.BaseDelay = 100 * time.Millisecond
+	}
+
+	var err error
+	delay := opts.BaseDelay
+	for attempt := 0; attempt < opts.MaxAttempts; attempt++ {
+		if attempt > 0 {
+			d := delay
+			if opts.Jitter {
+				d = d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+			}
+			select {
+			case <-time.After(d):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			delay *= 2
+			if opts.MaxDelay > 0 && delay > opts.MaxDelay {
+				delay = opts.MaxDelay
+			}
+		}
+		if err = fn(ctx); err == nil {
+			return nil
+		}
+		if opts.Retryable != nil && !opts.Retryable(err) {
+			return err
+		}
+	}
+	return err
+}
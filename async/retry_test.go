@@ -0,0 +1,88 @@
+package async_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cszatmary/goutils/async"
+	"github.com/cszatmary/goutils/errors"
+)
+
+func TestRetrySucceedsAfterFailures(t *testing.T) {
+	attempts := 0
+	err := async.Retry(context.Background(), async.RetryOptions{
+		MaxAttempts: 5,
+		BaseDelay:   time.Millisecond,
+	}, func(ctx context.Context) error {
+		attempts++
+		if attempts < 3 {
+			return errors.String("transient")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Errorf("want nil error, got %v", err)
+	}
+	if attempts != 3 {
+		t.Errorf("got %d attempts, want 3", attempts)
+	}
+}
+
+func TestRetryExhaustsAttempts(t *testing.T) {
+	const wantErr = errors.String("always fails")
+	attempts := 0
+	err := async.Retry(context.Background(), async.RetryOptions{
+		MaxAttempts: 3,
+		BaseDelay:   time.Millisecond,
+	}, func(ctx context.Context) error {
+		attempts++
+		return wantErr
+	})
+	if err != wantErr {
+		t.Errorf("got %v, want %v", err, wantErr)
+	}
+	if attempts != 3 {
+		t.Errorf("got %d attempts, want 3", attempts)
+	}
+}
+
+func TestRetryNonRetryable(t *testing.T) {
+	const fatalErr = errors.String("bad request")
+	attempts := 0
+	err := async.Retry(context.Background(), async.RetryOptions{
+		MaxAttempts: 5,
+		BaseDelay:   time.Millisecond,
+		Retryable: func(err error) bool {
+			return err != fatalErr
+		},
+	}, func(ctx context.Context) error {
+		attempts++
+		return fatalErr
+	})
+	if err != fatalErr {
+		t.Errorf("got %v, want %v", err, fatalErr)
+	}
+	if attempts != 1 {
+		t.Errorf("got %d attempts, want 1", attempts)
+	}
+}
+
+func TestRetryContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	attempts := 0
+	err := async.Retry(ctx, async.RetryOptions{
+		MaxAttempts: 5,
+		BaseDelay:   time.Minute, // long enough that cancellation wins
+	}, func(ctx context.Context) error {
+		attempts++
+		cancel()
+		return errors.String("transient")
+	})
+	if err != context.Canceled {
+		t.Errorf("got %v, want %v", err, context.Canceled)
+	}
+	if attempts != 1 {
+		t.Errorf("got %d attempts, want 1", attempts)
+	}
+}
@@ -20,6 +20,8 @@
 package color
 
 import (
+	"bytes"
+	"io"
 	"os"
 	"strconv"
 	"strings"
@@ -42,7 +44,9 @@ const (
 
 var (
 	noColor = os.Getenv("NO_COLOR") != "" // value doesn't matter, only if it's set
-	shared  Colorer
+	// noColorOverride takes precedence over noColor if set via SetNoColor.
+	noColorOverride *bool
+	shared          Colorer
 )
 
 // IsNoColorEnvSet returns true if the NO_COLOR environment variable is set, regardless of its value.
@@ -51,6 +55,56 @@ func IsNoColorEnvSet() bool {
 	return noColor
 }
 
+// RefreshNoColor re-reads the NO_COLOR environment variable and clears any
+// override set by SetNoColor. NO_COLOR is normally only read once at program
+// start, so this is useful for long-running processes or tests that modify
+// the environment at runtime.
+func RefreshNoColor() {
+	noColor = os.Getenv("NO_COLOR") != ""
+	noColorOverride = nil
+}
+
+// SetNoColor overrides the NO_COLOR environment variable detection.
+// If true, colors are disabled everywhere as if NO_COLOR was set.
+// If false, colors behave as if NO_COLOR was not set, even if it is.
+// The override remains in effect until RefreshNoColor is called.
+//
+// The precedence is: SetNoColor override, then the NO_COLOR environment
+// variable, then Colorer.SetEnabled.
+func SetNoColor(b bool) {
+	noColorOverride = &b
+}
+
+// Snapshot captures the current global color state, i.e. whether colors are
+// enabled along with the NO_COLOR detection and any SetNoColor override, and
+// returns a function that restores it. This is intended for tests that modify
+// global state so they don't leak changes and become order-dependent.
+//
+//	defer color.Snapshot()()
+func Snapshot() func() {
+	prevDisabled := shared.disabled
+	prevNoColor := noColor
+	var prevOverride *bool
+	if noColorOverride != nil {
+		v := *noColorOverride
+		prevOverride = &v
+	}
+	return func() {
+		shared.disabled = prevDisabled
+		noColor = prevNoColor
+		noColorOverride = prevOverride
+	}
+}
+
+// isNoColor reports whether colors should be disabled due to NO_COLOR,
+// taking any SetNoColor override into account.
+func isNoColor() bool {
+	if noColorOverride != nil {
+		return *noColorOverride
+	}
+	return noColor
+}
+
 // Colorer allows for creating coloured strings. Using a Colorer instance allows
 // for modifying certain attributes that affect output locally instead of globally,
 // for example, disable colouring in a local context and not globally.
@@ -107,9 +161,48 @@ func (c *Colorer) White(s string) string {
 	return c.apply(s, fgWhite, fgReset)
 }
 
+// Wrap colors s using colorFn while preserving any nested colored strings within s.
+//
+// The color methods (Red, Green, etc.) remove any reset codes found in s,
+// which means a nested colored string will bleed its color into the text
+// that follows it. Wrap instead re-establishes the outer color after each
+// nested reset so the surrounding text keeps the outer color.
+//
+//	c.Wrap(c.Red, "a "+c.Green("b")+" c") // "a" and "c" are red, "b" is green
+//
+// colorFn should be one of the color methods on this Colorer.
+func (c *Colorer) Wrap(colorFn func(string) string, s string) string {
+	// Discover the start and reset codes by coloring an empty string.
+	// If colors are disabled this will be empty and there is nothing to do.
+	codes := colorFn("")
+	if codes == "" {
+		return s
+	}
+	const resetLen = len("\x1b[39m")
+	start := codes[:len(codes)-resetLen]
+	reset := codes[len(codes)-resetLen:]
+
+	var sb strings.Builder
+	sb.WriteString(start)
+	// Find each nested reset and re-apply the outer color after it.
+	// We are only dealing with ASCII so it's safe to look at individual bytes.
+	j := 0
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\x1b' && strings.HasPrefix(s[i:], reset) {
+			sb.WriteString(s[j : i+len(reset)])
+			sb.WriteString(start)
+			i += len(reset) - 1 // -1 to account for i++
+			j = i + 1
+		}
+	}
+	sb.WriteString(s[j:])
+	sb.WriteString(reset)
+	return sb.String()
+}
+
 func (c *Colorer) apply(s string, start, end ansiCode) string {
 	// NO_COLOR always takes precedence.
-	if noColor || c.disabled {
+	if isNoColor() || c.disabled {
 		return s
 	}
 
@@ -188,3 +281,159 @@ func Cyan(s string) string {
 func White(s string) string {
 	return shared.White(s)
 }
+
+// Wrap colors s using colorFn while preserving any nested colored strings within s.
+// See Colorer.Wrap for more details.
+func Wrap(colorFn func(string) string, s string) string {
+	return shared.Wrap(colorFn, s)
+}
+
+// Painter is the method set shared by colouring backends. It is satisfied by
+// both Colorer and HTMLColorer, allowing code to be written against Painter
+// and target either ANSI or HTML output.
+type Painter interface {
+	Black(s string) string
+	Red(s string) string
+	Green(s string) string
+	Yellow(s string) string
+	Blue(s string) string
+	Magenta(s string) string
+	Cyan(s string) string
+	White(s string) string
+}
+
+var (
+	_ Painter = (*Colorer)(nil)
+	_ Painter = (*HTMLColorer)(nil)
+)
+
+// HTMLColorer creates coloured strings by wrapping them in HTML span elements
+// instead of ANSI escape codes. This is useful for producing coloured HTML
+// reports, e.g. CI summaries, using the same code paths as terminal output.
+//
+// The input string is not HTML-escaped, that is the caller's responsibility.
+//
+// A zero value HTMLColorer is a valid HTMLColorer ready for use.
+// Colors are enabled by default. NO_COLOR has no effect since it only
+// applies to terminal output.
+type HTMLColorer struct {
+	disabled bool // disabled so the zero value is enabled
+}
+
+// SetEnabled sets whether color is enabled or disabled.
+// If disabled, strings are returned unmodified with no span element.
+func (c *HTMLColorer) SetEnabled(e bool) {
+	c.disabled = !e
+}
+
+// Black creates a black colored string.
+func (c *HTMLColorer) Black(s string) string {
+	return c.apply(s, "black")
+}
+
+// Red creates a red colored string.
+func (c *HTMLColorer) Red(s string) string {
+	return c.apply(s, "red")
+}
+
+// Green creates a green colored string.
+func (c *HTMLColorer) Green(s string) string {
+	return c.apply(s, "green")
+}
+
+// Yellow creates a yellow colored string.
+func (c *HTMLColorer) Yellow(s string) string {
+	return c.apply(s, "yellow")
+}
+
+// Blue creates a blue colored string.
+func (c *HTMLColorer) Blue(s string) string {
+	return c.apply(s, "blue")
+}
+
+// Magenta creates a magenta colored string.
+func (c *HTMLColorer) Magenta(s string) string {
+	return c.apply(s, "magenta")
+}
+
+// Cyan creates a cyan colored string.
+func (c *HTMLColorer) Cyan(s string) string {
+	return c.apply(s, "cyan")
+}
+
+// White creates a white colored string.
+func (c *HTMLColorer) White(s string) string {
+	return c.apply(s, "white")
+}
+
+func (c *HTMLColorer) apply(s, color string) string {
+	if c.disabled {
+		return s
+	}
+	return `<span style="color:` + color + `">` + s + `</span>`
+}
+
+// Strip removes all ANSI escape sequences from s.
+// If s contains no escape sequences, s is returned as is.
+func Strip(s string) string {
+	if !strings.ContainsRune(s, '\x1b') {
+		return s
+	}
+	var sb strings.Builder
+	sb.Grow(len(s))
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\x1b' && i+1 < len(s) && s[i+1] == '[' {
+			// CSI sequence: skip the parameter and intermediate bytes
+			// until the final byte (0x40-0x7e) which terminates the sequence.
+			j := i + 2
+			for j < len(s) && (s[j] < 0x40 || s[j] > 0x7e) {
+				j++
+			}
+			i = j // also skips the final byte due to i++
+			continue
+		}
+		sb.WriteByte(s[i])
+	}
+	return sb.String()
+}
+
+// lineColorer is the io.Writer returned by LineColorer.
+type lineColorer struct {
+	w        io.Writer
+	classify func(line string) func(string) string
+	buf      bytes.Buffer
+}
+
+// LineColorer returns an io.Writer that buffers writes per line and colors
+// each complete line using the color function returned by classify, e.g.
+// color.Red for lines containing errors. If classify returns nil the line is
+// written unchanged. This is handy for auto-coloring subprocess output by
+// wrapping its stderr.
+//
+// The color functions honor the global disabled and NO_COLOR state as usual,
+// so classification is free to always return a color function.
+//
+// Partial lines are buffered until their newline arrives, so interleaved
+// writes do not split a line's coloring.
+func LineColorer(w io.Writer, classify func(line string) func(string) string) io.Writer {
+	return &lineColorer{w: w, classify: classify}
+}
+
+func (lc *lineColorer) Write(p []byte) (int, error) {
+	lc.buf.Write(p)
+	for {
+		b := lc.buf.Bytes()
+		i := bytes.IndexByte(b, '\n')
+		if i < 0 {
+			return len(p), nil
+		}
+		line := string(b[:i])
+		lc.buf.Next(i + 1)
+		if colorFn := lc.classify(line); colorFn != nil {
+			line = colorFn(line)
+		}
+		if _, err := io.WriteString(lc.w, line+"\n"); err != nil {
+			return len(p), err
+		}
+	}
+}
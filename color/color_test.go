@@ -1,6 +1,9 @@
 package color_test
 
 import (
+	"bytes"
+	"io"
+	"strings"
 	"testing"
 
 	"github.com/cszatmary/goutils/color"
@@ -92,6 +95,71 @@ func TestStripReset(t *testing.T) {
 	}
 }
 
+func TestWrap(t *testing.T) {
+	color.SetEnabled(true)
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{
+			"no nested colors",
+			"foo bar",
+			"\x1b[31mfoo bar\x1b[39m",
+		},
+		{
+			"nested color",
+			"foo " + color.Green("bar") + " baz",
+			"\x1b[31mfoo \x1b[32mbar\x1b[39m\x1b[31m baz\x1b[39m",
+		},
+		{
+			"multiple nested colors",
+			color.Green("foo") + " and " + color.Blue("bar"),
+			"\x1b[31m\x1b[32mfoo\x1b[39m\x1b[31m and \x1b[34mbar\x1b[39m\x1b[31m\x1b[39m",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := color.Wrap(color.Red, tt.in)
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWrapDisabled(t *testing.T) {
+	color.SetEnabled(false)
+	defer color.SetEnabled(true)
+	got := color.Wrap(color.Red, "foo bar")
+	want := "foo bar"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestStrip(t *testing.T) {
+	color.SetEnabled(true)
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"no escapes", "foo bar", "foo bar"},
+		{"single color", color.Red("foo bar"), "foo bar"},
+		{"nested colors", color.Wrap(color.Red, "a "+color.Green("b")+" c"), "a b c"},
+		{"other sequence", "\x1b[1;31mbold red\x1b[0m", "bold red"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := color.Strip(tt.in)
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestColorDisabled(t *testing.T) {
 	color.SetEnabled(false)
 	got := color.Red("foo bar")
@@ -127,3 +195,128 @@ func BenchmarkRed(b *testing.B) {
 		}
 	})
 }
+
+func TestSetNoColor(t *testing.T) {
+	color.SetEnabled(true)
+	t.Cleanup(color.RefreshNoColor)
+
+	color.SetNoColor(true)
+	if got, want := color.Red("foo bar"), "foo bar"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	color.SetNoColor(false)
+	if got, want := color.Red("foo bar"), "\x1b[31mfoo bar\x1b[39m"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestRefreshNoColor(t *testing.T) {
+	color.SetEnabled(true)
+	// Register before Setenv so the refresh runs after the env is restored.
+	t.Cleanup(color.RefreshNoColor)
+	t.Setenv("NO_COLOR", "1")
+
+	// The env var was read at init so it shouldn't take effect until refreshed.
+	color.RefreshNoColor()
+	if got, want := color.Red("foo bar"), "foo bar"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if !color.IsNoColorEnvSet() {
+		t.Error("IsNoColorEnvSet() = false, want true")
+	}
+
+	t.Setenv("NO_COLOR", "")
+	color.RefreshNoColor()
+	if got, want := color.Red("foo bar"), "\x1b[31mfoo bar\x1b[39m"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestHTMLColorer(t *testing.T) {
+	var c color.HTMLColorer
+	tests := []struct {
+		name    string
+		colorFn func(string) string
+		want    string
+	}{
+		{"red", c.Red, `<span style="color:red">foo bar</span>`},
+		{"green", c.Green, `<span style="color:green">foo bar</span>`},
+		{"magenta", c.Magenta, `<span style="color:magenta">foo bar</span>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.colorFn("foo bar"); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHTMLColorerDisabled(t *testing.T) {
+	var c color.HTMLColorer
+	c.SetEnabled(false)
+	if got, want := c.Red("foo bar"), "foo bar"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	color.SetEnabled(true)
+	restore := color.Snapshot()
+	color.SetEnabled(false)
+	color.SetNoColor(true)
+	if got, want := color.Red("foo bar"), "foo bar"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	restore()
+	if got, want := color.Red("foo bar"), "\x1b[31mfoo bar\x1b[39m"; got != want {
+		t.Errorf("got %q after restore, want %q", got, want)
+	}
+}
+
+func TestLineColorer(t *testing.T) {
+	color.SetEnabled(true)
+	classify := func(line string) func(string) string {
+		switch {
+		case strings.Contains(line, "error"):
+			return color.Red
+		case strings.Contains(line, "warning"):
+			return color.Yellow
+		}
+		return nil
+	}
+
+	var buf bytes.Buffer
+	w := color.LineColorer(&buf, classify)
+	// Write in fragments to make sure partial lines are buffered.
+	for _, p := range []string{"error: it", " broke\nwarning: careful\nall good\npartial"} {
+		if _, err := io.WriteString(w, p); err != nil {
+			t.Fatalf("want nil error, got %v", err)
+		}
+	}
+
+	want := "\x1b[31merror: it broke\x1b[39m\n" +
+		"\x1b[33mwarning: careful\x1b[39m\n" +
+		"all good\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestLineColorerDisabled(t *testing.T) {
+	color.SetEnabled(true)
+	restore := color.Snapshot()
+	defer restore()
+	color.SetNoColor(true)
+
+	var buf bytes.Buffer
+	w := color.LineColorer(&buf, func(string) func(string) string { return color.Red })
+	if _, err := io.WriteString(w, "error: it broke\n"); err != nil {
+		t.Fatalf("want nil error, got %v", err)
+	}
+	if got, want := buf.String(), "error: it broke\n"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -4,11 +4,20 @@
 package command
 
 import (
+	"bufio"
+	"bytes"
 	"context"
 	"fmt"
 	"io"
+	"log/slog"
+	"os"
 	"os/exec"
+	"path/filepath"
+	"sort"
 	"strings"
+
+	"github.com/cszatmary/goutils/color"
+	"github.com/cszatmary/goutils/progress"
 )
 
 // Exists checks if the command exists on the system by seeing if it's in the user's PATH.
@@ -20,11 +29,12 @@ func Exists(command string) bool {
 // Command manages the configuration of a command
 // that will be run in a child process.
 type Command struct {
-	stdin  io.Reader
-	stdout io.Writer
-	stderr io.Writer
-	env    map[string]string
-	dir    string
+	stdin      io.Reader
+	stdout     io.Writer
+	stderr     io.Writer
+	env        map[string]string
+	dir        string
+	stripColor bool
 }
 
 // New creates a command instance from the given options.
@@ -71,6 +81,33 @@ func WithEnv(env map[string]string) Option {
 	}
 }
 
+// WithEnvOverride sets the environment variables for the process the command
+// will be run in, starting from the current process's environment.
+// The given overrides are applied on top of the inherited environment,
+// and any unset keys are removed from it.
+//
+// Unlike WithEnv, which sets the environment to exactly the given variables,
+// this allows selectively modifying the inherited environment.
+func WithEnvOverride(overrides map[string]string, unset ...string) Option {
+	return func(c *Command) {
+		env := make(map[string]string)
+		for _, kv := range os.Environ() {
+			k, v, ok := strings.Cut(kv, "=")
+			if !ok {
+				continue
+			}
+			env[k] = v
+		}
+		for k, v := range overrides {
+			env[k] = v
+		}
+		for _, k := range unset {
+			delete(env, k)
+		}
+		c.env = env
+	}
+}
+
 // WithDir sets the directory the command should be run in.
 func WithDir(dir string) Option {
 	return func(c *Command) {
@@ -78,6 +115,16 @@ func WithDir(dir string) Option {
 	}
 }
 
+// WithStripColor sets whether ANSI escape sequences should be removed from the
+// command's output before it is written to the configured stdout/stderr writers.
+// This is useful when capturing the output of a program that emits colors,
+// so that escape codes don't end up in logs or error messages.
+func WithStripColor(b bool) Option {
+	return func(c *Command) {
+		c.stripColor = b
+	}
+}
+
 // Exec executes the named program with the given arguments.
 //
 // The provided context can be used to kill the process if the context
@@ -89,9 +136,15 @@ func (c *Command) Exec(ctx context.Context, name string, args ...string) error {
 	}
 	if c.stdout != nil {
 		cmd.Stdout = c.stdout
+		if c.stripColor {
+			cmd.Stdout = &stripWriter{w: c.stdout}
+		}
 	}
 	if c.stderr != nil {
 		cmd.Stderr = c.stderr
+		if c.stripColor {
+			cmd.Stderr = &stripWriter{w: c.stderr}
+		}
 	}
 	if c.env != nil {
 		for k, v := range c.env {
@@ -109,8 +162,173 @@ func (c *Command) Exec(ctx context.Context, name string, args ...string) error {
 	return nil
 }
 
+// String returns a shell-safe rendering of the command that would be run by
+// Exec with the given name and args, including any configured environment
+// variables and directory. Arguments containing whitespace or shell special
+// characters are quoted. This is intended for audit logs and dry-run output.
+//
+// The returned string is for display purposes, it is not guaranteed to be
+// reparsable into the exact same command.
+func (c *Command) String(name string, args ...string) string {
+	var sb strings.Builder
+	if c.dir != "" {
+		sb.WriteString("cd ")
+		sb.WriteString(shellQuote(c.dir))
+		sb.WriteString(" && ")
+	}
+	// Sort the env vars so the output is deterministic.
+	envKeys := make([]string, 0, len(c.env))
+	for k := range c.env {
+		envKeys = append(envKeys, k)
+	}
+	sort.Strings(envKeys)
+	for _, k := range envKeys {
+		sb.WriteString(k)
+		sb.WriteByte('=')
+		sb.WriteString(shellQuote(c.env[k]))
+		sb.WriteByte(' ')
+	}
+	sb.WriteString(shellQuote(name))
+	for _, arg := range args {
+		sb.WriteByte(' ')
+		sb.WriteString(shellQuote(arg))
+	}
+	return sb.String()
+}
+
+// Resolve returns the absolute path of the executable that would be run for
+// the named program, resolved through the user's PATH. This allows recording
+// exactly which binary would be executed before running it.
+func (c *Command) Resolve(name string) (string, error) {
+	path, err := exec.LookPath(name)
+	if err != nil {
+		return "", fmt.Errorf("command: failed to resolve %q: %w", name, err)
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", fmt.Errorf("command: failed to resolve %q: %w", name, err)
+	}
+	return abs, nil
+}
+
+// shellQuote quotes s so it is safe to use as a single shell word.
+// If s contains no special characters it is returned as is.
+func shellQuote(s string) string {
+	if s == "" {
+		return "''"
+	}
+	if !strings.ContainsAny(s, " \t\n'\"\\$&|;<>()*?[]{}#~`!") {
+		return s
+	}
+	// Single quotes preserve everything literally. A single quote itself
+	// is rendered by closing the quotes, escaping it, and reopening them.
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// stripWriter removes ANSI escape sequences from data before writing it to w.
+// Subprocess output arrives in arbitrary pipe-sized chunks, so an escape
+// sequence may be split across writes. A trailing incomplete sequence is held
+// back and prepended to the next write so it can be stripped whole; an
+// unterminated sequence at the end of the output is simply dropped.
+type stripWriter struct {
+	w io.Writer
+	// pending holds the prefix of an escape sequence whose final byte
+	// has not arrived yet.
+	pending []byte
+}
+
+func (sw *stripWriter) Write(p []byte) (int, error) {
+	data := p
+	if len(sw.pending) > 0 {
+		data = append(sw.pending, p...)
+		sw.pending = nil
+	}
+	if i := incompleteEscape(data); i >= 0 {
+		sw.pending = append(sw.pending, data[i:]...)
+		data = data[:i]
+	}
+	n, err := sw.w.Write([]byte(color.Strip(string(data))))
+	if err != nil {
+		// The stripped data doesn't map one to one onto p, so the best
+		// that can be reported is that not all of p was consumed.
+		if n > len(p) {
+			n = len(p)
+		}
+		return n, err
+	}
+	return len(p), nil
+}
+
+// incompleteEscape returns the index where a trailing CSI escape sequence
+// starts in p if its final byte has not arrived yet, or -1 if p does not
+// end mid-sequence.
+func incompleteEscape(p []byte) int {
+	i := bytes.LastIndexByte(p, '\x1b')
+	if i < 0 {
+		return -1
+	}
+	if i == len(p)-1 {
+		// A lone ESC, the '[' may be in the next write.
+		return i
+	}
+	if p[i+1] != '[' {
+		return -1
+	}
+	// The sequence is complete once the final byte (0x40-0x7e) arrives.
+	for j := i + 2; j < len(p); j++ {
+		if p[j] >= 0x40 && p[j] <= 0x7e {
+			return -1
+		}
+	}
+	return i
+}
+
 // Exec executes the named program with the given arguments.
 // This is a shorthand for when the default command options wish to be used.
 func Exec(ctx context.Context, name string, args ...string) error {
 	return New().Exec(ctx, name, args...)
 }
+
+// ExecLogged executes the named program with the given arguments and streams
+// its stdout and stderr to logger line by line at the given level.
+//
+// The provided context can be used to kill the process if the context
+// becomes done before the program completes on its own.
+//
+// level must be one of the standard slog levels (debug, info, warn, error).
+func ExecLogged(ctx context.Context, logger progress.Logger, level slog.Level, name string, args ...string) error {
+	var logFunc func(string, ...any)
+	switch level {
+	case slog.LevelDebug:
+		logFunc = logger.Debug
+	case slog.LevelInfo:
+		logFunc = logger.Info
+	case slog.LevelWarn:
+		logFunc = logger.Warn
+	case slog.LevelError:
+		logFunc = logger.Error
+	default:
+		return fmt.Errorf("command: unsupported level %s(%d)", level.String(), level)
+	}
+
+	pr, pw := io.Pipe()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		s := bufio.NewScanner(pr)
+		for s.Scan() {
+			logFunc(s.Text())
+		}
+		if err := s.Err(); err != nil {
+			logger.Error("Error while reading command output", "err", err)
+		}
+		pr.Close()
+	}()
+
+	err := New(WithStdout(pw), WithStderr(pw)).Exec(ctx, name, args...)
+	// Close the write side so the scanner sees EOF, then wait for it to
+	// finish logging any remaining output before returning.
+	pw.Close()
+	<-done
+	return err
+}
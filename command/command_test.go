@@ -3,10 +3,13 @@ package command_test
 import (
 	"bytes"
 	"context"
+	"log/slog"
+	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/cszatmary/goutils/command"
+	"github.com/cszatmary/goutils/logutil"
 )
 
 func TestExists(t *testing.T) {
@@ -83,9 +86,166 @@ func TestExecWithDir(t *testing.T) {
 	}
 }
 
+func TestExecStripColor(t *testing.T) {
+	var buf bytes.Buffer
+	cmd := command.New(
+		command.WithStdout(&buf),
+		command.WithStripColor(true),
+	)
+	err := cmd.Exec(context.Background(), "printf", "\x1b[31mred\x1b[39m plain\n")
+	if err != nil {
+		t.Errorf("want nil error, got %v", err)
+	}
+	want := "red plain\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got stdout %q, want %q", got, want)
+	}
+}
+
+func TestExecStripColorSplitEscape(t *testing.T) {
+	var buf bytes.Buffer
+	cmd := command.New(
+		command.WithStdout(&buf),
+		command.WithStripColor(true),
+	)
+	// The sleep between the printfs forces the escape sequence to be split
+	// across two writes, exercising the buffering in stripWriter.
+	err := cmd.Exec(context.Background(), "sh", "-c", `printf '\033[3'; sleep 0.2; printf '1mred\033[39m\n'`)
+	if err != nil {
+		t.Errorf("want nil error, got %v", err)
+	}
+	want := "red\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got stdout %q, want %q", got, want)
+	}
+}
+
+func TestExecLogged(t *testing.T) {
+	var buf bytes.Buffer
+	logger := logutil.NewFormatLogger(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: logutil.RemoveKeys(slog.TimeKey),
+	}))
+	err := command.ExecLogged(context.Background(), logger, slog.LevelInfo, "sh", "-c", "echo a; echo b")
+	if err != nil {
+		t.Errorf("want nil error, got %v", err)
+	}
+	want := "level=INFO msg=a\nlevel=INFO msg=b\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got logs\n\t%s\nwant\n\t%s", got, want)
+	}
+}
+
+func TestExecLoggedInvalidLevel(t *testing.T) {
+	logger := logutil.NewFormatLogger(slog.NewTextHandler(bytes.NewBuffer(nil), nil))
+	err := command.ExecLogged(context.Background(), logger, slog.LevelInfo+1, "echo", "hi")
+	if err == nil {
+		t.Error("want non-nil error, got nil")
+	}
+}
+
 func TestExecError(t *testing.T) {
 	err := command.Exec(context.Background(), "notacmd", "Hello World")
 	if err == nil {
 		t.Error("want non-nil error, got nil")
 	}
 }
+
+func TestCommandString(t *testing.T) {
+	tests := []struct {
+		name string
+		cmd  *command.Command
+		prog string
+		args []string
+		want string
+	}{
+		{
+			name: "simple",
+			cmd:  command.New(),
+			prog: "git",
+			args: []string{"status"},
+			want: "git status",
+		},
+		{
+			name: "arg with spaces",
+			cmd:  command.New(),
+			prog: "git",
+			args: []string{"commit", "-m", "hello world"},
+			want: "git commit -m 'hello world'",
+		},
+		{
+			name: "arg with single quote",
+			cmd:  command.New(),
+			prog: "echo",
+			args: []string{"it's"},
+			want: `echo 'it'\''s'`,
+		},
+		{
+			name: "empty arg",
+			cmd:  command.New(),
+			prog: "echo",
+			args: []string{""},
+			want: "echo ''",
+		},
+		{
+			name: "env and dir",
+			cmd: command.New(
+				command.WithEnv(map[string]string{"FOO": "bar baz", "A": "1"}),
+				command.WithDir("/tmp/my dir"),
+			),
+			prog: "make",
+			args: []string{"build"},
+			want: "cd '/tmp/my dir' && A=1 FOO='bar baz' make build",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cmd.String(tt.prog, tt.args...); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCommandResolve(t *testing.T) {
+	got, err := command.New().Resolve("sh")
+	if err != nil {
+		t.Fatalf("want nil error, got %v", err)
+	}
+	if !filepath.IsAbs(got) {
+		t.Errorf("got %q, want an absolute path", got)
+	}
+	if filepath.Base(got) != "sh" {
+		t.Errorf("got %q, want path to sh", got)
+	}
+
+	if _, err := command.New().Resolve("notacmd"); err == nil {
+		t.Error("want non-nil error, got nil")
+	}
+}
+
+func TestWithEnvOverride(t *testing.T) {
+	t.Setenv("COMMAND_TEST_INHERITED", "inherited")
+	t.Setenv("COMMAND_TEST_OVERRIDDEN", "original")
+	t.Setenv("COMMAND_TEST_UNSET", "should be removed")
+
+	buf := &bytes.Buffer{}
+	c := command.New(
+		command.WithStdout(buf),
+		command.WithEnvOverride(map[string]string{
+			"COMMAND_TEST_OVERRIDDEN": "overridden",
+		}, "COMMAND_TEST_UNSET"),
+	)
+	err := c.Exec(
+		context.Background(),
+		"sh", "-c",
+		`echo "$COMMAND_TEST_INHERITED|$COMMAND_TEST_OVERRIDDEN|$COMMAND_TEST_UNSET"`,
+	)
+	if err != nil {
+		t.Fatalf("want nil error, got %v", err)
+	}
+	got := strings.TrimSpace(buf.String())
+	want := "inherited|overridden|"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
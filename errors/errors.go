@@ -26,6 +26,7 @@
 package errors
 
 import (
+	"context"
 	stderrors "errors"
 	"fmt"
 	"strings"
@@ -190,6 +191,126 @@ func (e *Error) Unwrap() error {
 	return e.Err
 }
 
+// Ops returns the sequence of operations from err's chain, outermost first.
+// It walks the chain by repeatedly calling Unwrap, collecting the Op of each
+// Error encountered. Empty ops are skipped.
+//
+// This can be useful for metrics tagging or building a breadcrumb trail of
+// where an error occurred.
+func Ops(err error) []Op {
+	var ops []Op
+	for err != nil {
+		if e, ok := err.(*Error); ok && e.Op != "" {
+			ops = append(ops, e.Op)
+		}
+		err = Unwrap(err)
+	}
+	return ops
+}
+
+// kindSet is the composite Kind returned by Kinds.
+// It holds a pointer to its constituents so it stays comparable.
+type kindSet struct {
+	name string
+	ks   *[]Kind
+}
+
+// Kinds returns a Kind composed of ks. Its Kind method joins the names of the
+// constituents with "/", e.g. Kinds(network, timeout).Kind() == "network/timeout".
+// Use KindIs to match a composite against its constituents, since '==' only
+// matches the composite itself.
+func Kinds(ks ...Kind) Kind {
+	names := make([]string, len(ks))
+	c := make([]Kind, len(ks))
+	for i, k := range ks {
+		names[i] = k.Kind()
+		c[i] = k
+	}
+	return kindSet{name: strings.Join(names, "/"), ks: &c}
+}
+
+func (k kindSet) Kind() string {
+	return k.name
+}
+
+// KindIs reports whether k matches target. A Kind matches itself, and a
+// composite Kind created with Kinds matches any of its constituents.
+// This allows middleware to categorize an error with several kinds and have
+// callers match on any one of them.
+func KindIs(k, target Kind) bool {
+	if k == nil || target == nil {
+		return k == nil && target == nil
+	}
+	if k == target {
+		return true
+	}
+	if ks, ok := k.(kindSet); ok {
+		for _, kk := range *ks.ks {
+			if KindIs(kk, target) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// KindOf returns the Kind of err: the Kind of the outermost Error in err's
+// chain that has one. It returns nil if no Error in the chain has a Kind.
+func KindOf(err error) Kind {
+	for err != nil {
+		if e, ok := err.(*Error); ok && e.Kind != nil {
+			return e.Kind
+		}
+		err = Unwrap(err)
+	}
+	return nil
+}
+
+// WithValue returns an error that wraps err and carries the given key/value pair.
+// The value does not appear in the error message, it is machine-readable metadata
+// that can be retrieved later using Value. This is analogous to context.WithValue
+// but for errors, e.g. attaching an HTTP status or a retry delay for middleware
+// to act on without polluting the human-readable message.
+//
+// The key should be comparable. As with context, it is recommended to use an
+// unexported custom type for keys to avoid collisions between packages.
+//
+// If err is nil, WithValue returns nil.
+func WithValue(err error, key, value any) error {
+	if err == nil {
+		return nil
+	}
+	return &valueError{err: err, key: key, value: value}
+}
+
+// Value traverses err's chain looking for a value associated with key,
+// as attached by WithValue. It returns the value and true if found,
+// or nil and false otherwise. The outermost value for a key wins.
+func Value(err error, key any) (any, bool) {
+	for err != nil {
+		if ve, ok := err.(*valueError); ok && ve.key == key {
+			return ve.value, true
+		}
+		err = Unwrap(err)
+	}
+	return nil, false
+}
+
+// valueError wraps an error with a key/value pair. The pair is invisible in
+// the error message, Error and Unwrap simply delegate to the wrapped error.
+type valueError struct {
+	err        error
+	key, value any
+}
+
+func (e *valueError) Error() string {
+	return e.err.Error()
+}
+
+func (e *valueError) Unwrap() error {
+	return e.err
+}
+
 // List is a list of errors. It allows for operations to keep track of
 // multiple errors and return them as a single error value.
 type List []error
@@ -205,6 +326,31 @@ func (e List) Error() string {
 	return sb.String()
 }
 
+// Unwrap returns the errors contained in the List.
+// This allows List to work with the multi-error support in the standard library's
+// Is and As functions, which traverse errors whose Unwrap method returns []error.
+func (e List) Unwrap() []error {
+	return e
+}
+
+// Collapse converts list into a single Error whose Reason summarizes the
+// number of errors that occurred, e.g. "3 operations failed". The list itself
+// is kept as the wrapped error so the individual errors are still accessible.
+// This is useful when returning aggregated errors to a layer that expects a
+// single Error.
+//
+// If list is empty, Collapse returns nil.
+func Collapse(list List, op Op, kind Kind) error {
+	if len(list) == 0 {
+		return nil
+	}
+	reason := fmt.Sprintf("%d operations failed", len(list))
+	if len(list) == 1 {
+		reason = "1 operation failed"
+	}
+	return &Error{Kind: kind, Reason: reason, Op: op, Err: list}
+}
+
 func (e List) Format(s fmt.State, verb rune) {
 	switch verb {
 	case 'v':
@@ -251,6 +397,54 @@ func Unwrap(err error) error {
 	return stderrors.Unwrap(err)
 }
 
+// IsRetryable reports whether err represents a transient failure that is
+// worth retrying. It returns true if any error in err's chain:
+//
+//   - implements Temporary() bool or Timeout() bool returning true,
+//     e.g. a net.Error
+//   - is context.DeadlineExceeded
+//   - is an Error whose Kind implements Retryable() bool returning true
+//
+// This provides a sensible default predicate for async.RetryOptions.Retryable
+// grounded in this package's error model. Kinds opt in to being retryable by
+// implementing the optional Retryable method.
+func IsRetryable(err error) bool {
+	for ; err != nil; err = stderrors.Unwrap(err) {
+		if err == context.DeadlineExceeded {
+			return true
+		}
+		if te, ok := err.(interface{ Temporary() bool }); ok && te.Temporary() {
+			return true
+		}
+		if te, ok := err.(interface{ Timeout() bool }); ok && te.Timeout() {
+			return true
+		}
+		if e, ok := err.(*Error); ok && e.Kind != nil {
+			if rk, ok := e.Kind.(interface{ Retryable() bool }); ok && rk.Retryable() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Cause returns the root error of err's chain, i.e. the innermost error,
+// obtained by repeatedly calling Unwrap until an error that does not wrap
+// another is reached. If err does not wrap another error, err itself is returned.
+//
+// This is useful for retrieving the original underlying error,
+// e.g. an *os.PathError, without manually looping Unwrap.
+func Cause(err error) error {
+	for err != nil {
+		unwrapped := stderrors.Unwrap(err)
+		if unwrapped == nil {
+			return err
+		}
+		err = unwrapped
+	}
+	return err
+}
+
 // Is reports whether any error in err's chain matches target.
 //
 // The chain consists of err itself followed by the sequence of errors obtained by
@@ -1,7 +1,10 @@
 package errors_test
 
 import (
+	"context"
+	stderrors "errors"
 	"fmt"
+	"reflect"
 	"testing"
 
 	"github.com/cszatmary/goutils/errors"
@@ -206,3 +209,261 @@ func TestAs(t *testing.T) {
 		t.Errorf("got err\n\t%s\nwant\n\t%s", gotErr, pathErr)
 	}
 }
+
+func TestListUnwrap(t *testing.T) {
+	const eof errors.String = "EOF"
+	const timeout errors.String = "timeout"
+	list := errors.List{
+		fmt.Errorf("reading config: %w", eof),
+		timeout,
+	}
+	// The standard library's Is/As must traverse the List via multi-unwrap.
+	if !stderrors.Is(list, eof) {
+		t.Error("want list to contain eof")
+	}
+	if !stderrors.Is(list, timeout) {
+		t.Error("want list to contain timeout")
+	}
+
+	// A wrapped List must still traverse.
+	err := errors.Wrap(list, errors.Meta{
+		Kind:   internal,
+		Reason: "multiple failures",
+		Op:     errors.Op("config.Load"),
+	})
+	if !errors.Is(err, eof) {
+		t.Error("want wrapped list to contain eof")
+	}
+
+	// Mixing with the standard library's Join should also work.
+	joined := stderrors.Join(errors.List{eof}, timeout)
+	if !errors.Is(joined, eof) {
+		t.Error("want joined error to contain eof")
+	}
+}
+
+func TestCollapse(t *testing.T) {
+	const eof errors.String = "EOF"
+	const timeout errors.String = "timeout"
+	op := errors.Op("config.Load")
+
+	tests := []struct {
+		name    string
+		list    errors.List
+		wantMsg string
+	}{
+		{
+			name:    "empty list",
+			list:    errors.List{},
+			wantMsg: "",
+		},
+		{
+			name:    "single error",
+			list:    errors.List{eof},
+			wantMsg: "internal error: 1 operation failed: EOF",
+		},
+		{
+			name:    "multiple errors",
+			list:    errors.List{eof, timeout},
+			wantMsg: "internal error: 2 operations failed: EOF\ntimeout",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := errors.Collapse(tt.list, op, internal)
+			if len(tt.list) == 0 {
+				if err != nil {
+					t.Fatalf("want nil error, got %v", err)
+				}
+				return
+			}
+			if got := err.Error(); got != tt.wantMsg {
+				t.Errorf("got err\n\t%s\nwant\n\t%s", got, tt.wantMsg)
+			}
+			// The individual errors must still be accessible.
+			if !errors.Is(err, eof) {
+				t.Error("want err to contain eof")
+			}
+		})
+	}
+}
+
+func TestOps(t *testing.T) {
+	const eof errors.String = "EOF"
+	err := errors.Wrap(eof, errors.Meta{
+		Kind:   internal,
+		Reason: "unexpected end of file",
+		Op:     errors.Op("config.Read"),
+	})
+	err = errors.Wrap(err, errors.Meta{
+		Reason: "failed to load config",
+		Op:     errors.Op("config.Load"),
+	})
+	err = errors.Wrap(err, errors.Meta{
+		Reason: "unable to start",
+		Op:     errors.Op("app.Start"),
+	})
+
+	want := []errors.Op{"app.Start", "config.Load", "config.Read"}
+	got := errors.Ops(err)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got ops %v, want %v", got, want)
+	}
+
+	// A non-Error has no ops.
+	if ops := errors.Ops(eof); ops != nil {
+		t.Errorf("got ops %v, want nil", ops)
+	}
+}
+
+type valueKey string
+
+func TestWithValue(t *testing.T) {
+	const (
+		statusKey valueKey = "http_status"
+		retryKey  valueKey = "retry_after"
+	)
+	base := errors.String("service unavailable")
+	err := errors.WithValue(base, statusKey, 503)
+	err = fmt.Errorf("calling service: %w", err)
+	err = errors.WithValue(err, retryKey, 30)
+
+	// The message should be unaffected by the attached values.
+	if got, want := err.Error(), "calling service: service unavailable"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if v, ok := errors.Value(err, statusKey); !ok || v != 503 {
+		t.Errorf("got %v, %t; want 503, true", v, ok)
+	}
+	if v, ok := errors.Value(err, retryKey); !ok || v != 30 {
+		t.Errorf("got %v, %t; want 30, true", v, ok)
+	}
+	if v, ok := errors.Value(err, valueKey("missing")); ok {
+		t.Errorf("got %v, %t; want nil, false", v, ok)
+	}
+	// Is should still see through to the original error.
+	if !errors.Is(err, base) {
+		t.Error("want errors.Is to match the wrapped error")
+	}
+}
+
+func TestWithValueOverride(t *testing.T) {
+	const key valueKey = "http_status"
+	err := errors.WithValue(errors.String("oops"), key, 500)
+	err = errors.WithValue(err, key, 503)
+	// The outermost value should win.
+	if v, ok := errors.Value(err, key); !ok || v != 503 {
+		t.Errorf("got %v, %t; want 503, true", v, ok)
+	}
+}
+
+func TestWithValueNil(t *testing.T) {
+	if err := errors.WithValue(nil, valueKey("k"), 1); err != nil {
+		t.Errorf("got %v, want nil", err)
+	}
+}
+
+func TestCause(t *testing.T) {
+	root := stderrors.New("root cause")
+	wrapped := errors.Wrap(root, errors.Meta{Kind: internal, Reason: "something blew up", Op: "test.Foo"})
+	doubleWrapped := fmt.Errorf("while doing stuff: %w", wrapped)
+
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"multi-level chain", doubleWrapped, root},
+		{"errors.Error chain", wrapped, root},
+		{"non-wrapping error", root, root},
+		{"nil error", nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errors.Cause(tt.err); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// retryableKind is a Kind that declares its errors retryable.
+type retryableKind string
+
+func (k retryableKind) Kind() string  { return string(k) }
+func (retryableKind) Retryable() bool { return true }
+
+// temporaryError implements the Temporary method like net.Error.
+type temporaryError struct{}
+
+func (temporaryError) Error() string   { return "temporary failure" }
+func (temporaryError) Temporary() bool { return true }
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil error", nil, false},
+		{"plain error", stderrors.New("boom"), false},
+		{"temporary error", temporaryError{}, true},
+		{"wrapped temporary error", fmt.Errorf("request failed: %w", temporaryError{}), true},
+		{"deadline exceeded", context.DeadlineExceeded, true},
+		{"retryable kind", errors.New(retryableKind("unavailable"), "service unavailable", "test.Foo"), true},
+		{"non-retryable kind", errors.New(internal, "something blew up", "test.Foo"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errors.IsRetryable(tt.err); got != tt.want {
+				t.Errorf("got %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKinds(t *testing.T) {
+	composite := errors.Kinds(invalid, internal)
+	if got, want := composite.Kind(), "invalid operation/internal error"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	tests := []struct {
+		name   string
+		k      errors.Kind
+		target errors.Kind
+		want   bool
+	}{
+		{"composite matches itself", composite, composite, true},
+		{"composite matches first constituent", composite, invalid, true},
+		{"composite matches second constituent", composite, internal, true},
+		{"composite does not match other kind", composite, retryableKind("throttled"), false},
+		{"plain kind matches itself", internal, internal, true},
+		{"plain kind does not match other kind", internal, invalid, false},
+		{"nil matches nil", nil, nil, true},
+		{"nil does not match kind", nil, internal, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errors.KindIs(tt.k, tt.target); got != tt.want {
+				t.Errorf("got %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKindOf(t *testing.T) {
+	composite := errors.Kinds(invalid, internal)
+	err := errors.New(composite, "it broke", "test.Foo")
+	wrapped := fmt.Errorf("while doing stuff: %w", err)
+
+	if got := errors.KindOf(wrapped); !errors.KindIs(got, invalid) {
+		t.Errorf("got kind %v, want it to match invalid", got)
+	}
+	if got := errors.KindOf(stderrors.New("plain")); got != nil {
+		t.Errorf("got kind %v, want nil", got)
+	}
+	if got := errors.KindOf(nil); got != nil {
+		t.Errorf("got kind %v, want nil", got)
+	}
+}
@@ -36,6 +36,9 @@ import (
 	"fmt"
 	"io"
 	"os"
+
+	"github.com/cszatmary/goutils/errors"
+	"github.com/cszatmary/goutils/progress"
 )
 
 // ExitCoder defines a type that can provide an exit code.
@@ -85,12 +88,22 @@ func (e *Error) Error() string {
 
 func (e *Error) Format(s fmt.State, verb rune) {
 	if e.Err != nil {
-		_, _ = io.WriteString(s, "Error: ")
 		format := "%v\n"
 		if s.Flag('+') {
 			format = "%+v\n"
 		}
-		fmt.Fprintf(s, format, e.Err)
+		// If the exit was caused by multiple errors, enumerate each one
+		// on its own line under the header.
+		if list, ok := e.Err.(errors.List); ok {
+			_, _ = io.WriteString(s, "Errors:\n")
+			for _, err := range list {
+				_, _ = io.WriteString(s, "  ")
+				fmt.Fprintf(s, format, err)
+			}
+		} else {
+			_, _ = io.WriteString(s, "Error: ")
+			fmt.Fprintf(s, format, e.Err)
+		}
 	}
 	// If an error was just printed and a message is going to be printed,
 	// add an extra newline inbetween them.
@@ -128,15 +141,28 @@ type Exiter struct {
 	// additional tasks before exiting.
 	// If nil, it will be defaulted to os.Exit.
 	ExitFunc func(code int)
+	// CodeFunc derives an exit code from an error. If set, it is consulted
+	// before checking if the error implements ExitCoder. This allows keeping
+	// exit code policy in one place, e.g. mapping error categories to codes,
+	// without requiring callers to construct a fatal.Error each time.
+	// A return value less than 1 means the code could not be determined and
+	// the normal resolution continues.
+	CodeFunc func(err error) int
 }
 
 // Exit causes the program to exit. The exit code is determined based on err.
-// If err implements ExitCoder and the value of ExitCode is greater than zero,
-// it will be used. Otherwise, the exit code will be 1.
+// If CodeFunc is set and returns a value greater than zero, it will be used.
+// Otherwise, if err implements ExitCoder and the value of ExitCode is greater
+// than zero, it will be used. Otherwise, the exit code will be 1.
 func (e *Exiter) Exit(err error) {
 	var code int
-	if ec, ok := err.(ExitCoder); ok {
-		code = ec.ExitCode()
+	if e.CodeFunc != nil {
+		code = e.CodeFunc(err)
+	}
+	if code < 1 {
+		if ec, ok := err.(ExitCoder); ok {
+			code = ec.ExitCode()
+		}
 	}
 	// If the code couldn't be determined or an invalid code was provided,
 	// default to code to 1 since that is the general catch all error code.
@@ -167,6 +193,25 @@ func (e *Exiter) PrintAndExit(err error) {
 	e.Exit(err)
 }
 
+// LogAndExit logs err at Error level using logger and then causes the program
+// to exit. If err is an errors.Error, its Kind and Op are included as attrs
+// so the exit is captured with structured context by log aggregation.
+// The exit code is determined the same way as Exit.
+func (e *Exiter) LogAndExit(logger progress.Logger, err error) {
+	var args []any
+	var gerr *errors.Error
+	if errors.As(err, &gerr) {
+		if gerr.Kind != nil {
+			args = append(args, "kind", gerr.Kind.Kind())
+		}
+		if gerr.Op != "" {
+			args = append(args, "op", string(gerr.Op))
+		}
+	}
+	logger.Error(err.Error(), args...)
+	e.Exit(err)
+}
+
 // Exit causes the program to exit. The exit code is determined based on err.
 // If err implements ExitCoder and the value of ExitCode is greater than zero,
 // it will be used. Otherwise, the exit code will be 1.
@@ -175,6 +220,13 @@ func Exit(err error) {
 	e.Exit(err)
 }
 
+// LogAndExit logs err using logger and then causes the program to exit.
+// See Exiter.LogAndExit for more details.
+func LogAndExit(logger progress.Logger, err error) {
+	var e Exiter
+	e.LogAndExit(logger, err)
+}
+
 // PrintAndExit prints the error and then causes the program to exit.
 // The exit code is determined based on err. If err implements ExitCoder
 // and the value of ExitCode is greater than zero, it will be used.
@@ -3,10 +3,12 @@ package fatal_test
 import (
 	"bytes"
 	"fmt"
+	"reflect"
 	"testing"
 
 	"github.com/cszatmary/goutils/errors"
 	"github.com/cszatmary/goutils/fatal"
+	"github.com/cszatmary/goutils/progress/progresstest"
 )
 
 func TestExiterExit(t *testing.T) {
@@ -80,6 +82,19 @@ func TestExiterPrintAndExit(t *testing.T) {
 			wantCode:   2,
 			wantOutput: "Error: err everything broke\n\nSomething broke\n",
 		},
+		{
+			name: "fatal.Error with multiple causes",
+			err: &fatal.Error{
+				Code: 2,
+				Msg:  "Something broke",
+				Err: errors.List{
+					errors.String("first thing broke"),
+					errors.String("second thing broke"),
+				},
+			},
+			wantCode:   2,
+			wantOutput: "Errors:\n  first thing broke\n  second thing broke\n\nSomething broke\n",
+		},
 		{
 			name: "fatal.Error with detail",
 			err: &fatal.Error{
@@ -129,3 +144,84 @@ func (c coder) ExitCode() int {
 func (c coder) Error() string {
 	return fmt.Sprintf("Code: %d", c)
 }
+
+type notFoundError struct{}
+
+func (notFoundError) Error() string {
+	return "not found"
+}
+
+func TestExitCodeFunc(t *testing.T) {
+	codeFunc := func(err error) int {
+		var nfe notFoundError
+		if errors.As(err, &nfe) {
+			return 4
+		}
+		return 0
+	}
+	tests := []struct {
+		name     string
+		err      error
+		wantCode int
+	}{
+		{
+			name:     "CodeFunc matches",
+			err:      notFoundError{},
+			wantCode: 4,
+		},
+		{
+			name:     "CodeFunc takes precedence over ExitCoder",
+			err:      &fatal.Error{Code: 2, Err: notFoundError{}},
+			wantCode: 4,
+		},
+		{
+			name:     "falls back to ExitCoder",
+			err:      coder(3),
+			wantCode: 3,
+		},
+		{
+			name:     "falls back to default",
+			err:      errors.String("oops"),
+			wantCode: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var me mockExit
+			exiter := fatal.Exiter{ExitFunc: me.Exit, CodeFunc: codeFunc}
+			exiter.Exit(tt.err)
+			if me.code != tt.wantCode {
+				t.Errorf("got exit code %d, want %d", me.code, tt.wantCode)
+			}
+		})
+	}
+}
+
+type testKind string
+
+func (k testKind) Kind() string {
+	return string(k)
+}
+
+func TestLogAndExit(t *testing.T) {
+	_, r := progresstest.WithTracker(t)
+	var me mockExit
+	exiter := fatal.Exiter{ExitFunc: me.Exit}
+	err := errors.New(testKind("internal"), "everything broke", errors.Op("test.Foo"))
+	exiter.LogAndExit(r, err)
+
+	if me.code != 1 {
+		t.Errorf("got exit code %d, want 1", me.code)
+	}
+	logs := r.Logs()
+	if len(logs) != 1 {
+		t.Fatalf("got %d logs, want 1", len(logs))
+	}
+	if logs[0].Level != "error" || logs[0].Msg != err.Error() {
+		t.Errorf("got log %+v, want error %q", logs[0], err.Error())
+	}
+	wantArgs := []any{"kind", "internal", "op", "test.Foo"}
+	if !reflect.DeepEqual(logs[0].Args, wantArgs) {
+		t.Errorf("got args %v, want %v", logs[0].Args, wantArgs)
+	}
+}
@@ -5,11 +5,16 @@ import (
 	"archive/tar"
 	"bytes"
 	"compress/gzip"
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"sync"
+
+	"github.com/cszatmary/goutils/async"
 )
 
 const mkdirDefaultPerms = 0o755
@@ -31,6 +36,25 @@ func Exists(path string) bool {
 
 // Download creates or replaces a file at dst by reading from r.
 func Download(dst string, r io.Reader) (int64, error) {
+	return DownloadWithOptions(dst, r, DownloadOptions{})
+}
+
+// DownloadOptions is used to customize how DownloadWithOptions behaves.
+// All fields are optional.
+type DownloadOptions struct {
+	// DryRun causes the download to be skipped. Instead the action that
+	// would have been performed is written to DryRun.
+	DryRun io.Writer
+}
+
+// DownloadWithOptions is like Download but allows customizing the behaviour
+// of the download using opts.
+func DownloadWithOptions(dst string, r io.Reader, opts DownloadOptions) (int64, error) {
+	if opts.DryRun != nil {
+		fmt.Fprintf(opts.DryRun, "download to %s\n", dst)
+		return 0, nil
+	}
+
 	// Check if file exists
 	dstDir := filepath.Dir(dst)
 	if err := os.MkdirAll(dstDir, mkdirDefaultPerms); err != nil {
@@ -50,9 +74,92 @@ func Download(dst string, r io.Reader) (int64, error) {
 	return n, nil
 }
 
+// DownloadItem describes a single download to be performed by DownloadAll.
+type DownloadItem struct {
+	// Open returns the source to download the data from.
+	// The returned io.ReadCloser will be closed by DownloadAll.
+	// This keeps the file package agnostic of where the data comes from,
+	// e.g. an HTTP response body or another file.
+	Open func() (io.ReadCloser, error)
+	// Dst is the path the data should be downloaded to.
+	Dst string
+}
+
+// DownloadAllOptions is used to customize how DownloadAll behaves.
+// All fields are optional.
+type DownloadAllOptions struct {
+	// Concurrency is the max number of downloads that can run at once.
+	// If the value is zero or negative, there will be no limit.
+	Concurrency int
+	// OnComplete is called after each item has been downloaded, with the item
+	// and the number of bytes written. It may be called concurrently from
+	// multiple goroutines, so it must be safe for concurrent use.
+	OnComplete func(item DownloadItem, n int64)
+	// DryRun causes the downloads to be skipped. Instead the actions that
+	// would have been performed are written to DryRun.
+	// Open is not called for any item during a dry run.
+	DryRun io.Writer
+}
+
+// DownloadAll downloads all the given items concurrently. It is a higher-level
+// helper over Download for the common case of downloading many artifacts.
+//
+// If a download fails the remaining downloads still run to completion and an
+// errors.List containing each error is returned, in item order.
+func DownloadAll(ctx context.Context, items []DownloadItem, opts DownloadAllOptions) error {
+	if opts.DryRun != nil {
+		for _, item := range items {
+			fmt.Fprintf(opts.DryRun, "download to %s\n", item.Dst)
+		}
+		return nil
+	}
+
+	var g async.Group[int64]
+	g.SetMaxGoroutines(opts.Concurrency)
+	for _, item := range items {
+		item := item
+		g.Queue(func(ctx context.Context) (int64, error) {
+			rc, err := item.Open()
+			if err != nil {
+				return 0, fmt.Errorf("failed to open source for %q: %w", item.Dst, err)
+			}
+			defer rc.Close()
+			n, err := Download(item.Dst, rc)
+			if err != nil {
+				return 0, err
+			}
+			if opts.OnComplete != nil {
+				opts.OnComplete(item, n)
+			}
+			return n, nil
+		})
+	}
+	_, err := g.Wait(ctx)
+	return err
+}
+
 // CopyFile copies the regular file located at src to dst. Any intermediate directories in dst
 // that do not exists will be created. If src is not a regular file an error will be returned.
 func CopyFile(src, dst string) error {
+	return CopyFileWithOptions(src, dst, CopyFileOptions{})
+}
+
+// CopyFileOptions is used to customize how CopyFileWithOptions behaves.
+// All fields are optional.
+type CopyFileOptions struct {
+	// PreserveOwner preserves the user and group ownership of the copied file.
+	// This generally requires running as root; if the process lacks the permission
+	// to change ownership it is silently skipped. Ownership is only supported on
+	// Unix-like platforms, on other platforms this option is ignored.
+	PreserveOwner bool
+	// DryRun causes the copy to be skipped. Instead the action that
+	// would have been performed is written to DryRun.
+	DryRun io.Writer
+}
+
+// CopyFileWithOptions is like CopyFile but allows customizing the behaviour
+// of the copy using opts.
+func CopyFileWithOptions(src, dst string, opts CopyFileOptions) error {
 	info, err := os.Lstat(src)
 	if err != nil {
 		return fmt.Errorf("failed to get info of %q: %w", src, err)
@@ -60,12 +167,17 @@ func CopyFile(src, dst string) error {
 	if !info.Mode().IsRegular() {
 		return fmt.Errorf("%w: %q", ErrNotRegularFile, src)
 	}
-	return copyFile(src, dst, info)
+	return copyFile(src, dst, info, opts)
 }
 
 // copyFile is the actual implementation of CopyFile. It assumes that src
 // has already been verified to be a regular file.
-func copyFile(src, dst string, info os.FileInfo) error {
+func copyFile(src, dst string, info os.FileInfo, opts CopyFileOptions) error {
+	if opts.DryRun != nil {
+		fmt.Fprintf(opts.DryRun, "copy file %s to %s\n", src, dst)
+		return nil
+	}
+
 	dir := filepath.Dir(dst)
 	if err := os.MkdirAll(dir, mkdirDefaultPerms); err != nil {
 		return fmt.Errorf("failed to create directory %q: %w", dir, err)
@@ -86,13 +198,69 @@ func copyFile(src, dst string, info os.FileInfo) error {
 	if _, err = io.Copy(f, s); err != nil {
 		return fmt.Errorf("failed to copy %q to %q: %w", src, dst, err)
 	}
+	if opts.PreserveOwner {
+		if uid, gid, ok := owner(info); ok {
+			if err := lchown(dst, uid, gid); err != nil {
+				return fmt.Errorf("failed to change ownership of %q: %w", dst, err)
+			}
+		}
+	}
 	return nil
 }
 
+// CopyFileContents copies the contents of the regular file located at src to w,
+// returning the number of bytes written. If src is not a regular file an error
+// will be returned.
+//
+// This is a lower-level alternative to CopyFile for when the destination is an
+// already-open file or some other writer.
+func CopyFileContents(w io.Writer, src string) (int64, error) {
+	info, err := os.Lstat(src)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get info of %q: %w", src, err)
+	}
+	if !info.Mode().IsRegular() {
+		return 0, fmt.Errorf("%w: %q", ErrNotRegularFile, src)
+	}
+
+	s, err := os.Open(src)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open source file %q: %w", src, err)
+	}
+	defer s.Close()
+
+	n, err := io.Copy(w, s)
+	if err != nil {
+		return n, fmt.Errorf("failed to copy contents of %q: %w", src, err)
+	}
+	return n, nil
+}
+
 // CopyDirContents copies all contents from the directory src to the directory dst.
 // Only regular files and directories will be copied. If src or dst is not a directory,
 // and error will be returned. If dst does not exists, it will be created.
+// Every source directory is recreated in dst, including empty ones, so the
+// directory tree structure is preserved exactly.
 func CopyDirContents(src, dst string) error {
+	return CopyDirContentsWithOptions(src, dst, CopyDirContentsOptions{})
+}
+
+// CopyDirContentsOptions is used to customize how CopyDirContentsWithOptions behaves.
+// All fields are optional.
+type CopyDirContentsOptions struct {
+	// DryRun causes the copy to be skipped. Instead the actions that
+	// would have been performed are written to DryRun.
+	DryRun io.Writer
+	// OnSkip is called for each entry that is skipped because it is not a
+	// regular file or directory, e.g. a socket or device file. path is the
+	// source path of the skipped entry and reason describes why it was skipped.
+	// This allows callers to warn the user about a partial copy.
+	OnSkip func(path string, reason string)
+}
+
+// CopyDirContentsWithOptions is like CopyDirContents but allows customizing
+// the behaviour of the copy using opts.
+func CopyDirContentsWithOptions(src, dst string, opts CopyDirContentsOptions) error {
 	info, err := os.Lstat(src)
 	if err != nil {
 		return fmt.Errorf("failed to get info of %q: %w", src, err)
@@ -100,14 +268,16 @@ func CopyDirContents(src, dst string) error {
 	if !info.IsDir() {
 		return fmt.Errorf("%w: %q", ErrNotDir, src)
 	}
-	return copyDirContents(src, dst, info)
+	return copyDirContents(src, dst, info, opts)
 }
 
 // copyDirContents is the actual implementation of CopyDirContents. It assumes that src
 // has already been verified to be a directory file.
-func copyDirContents(src, dst string, info os.FileInfo) error {
-	// Make sure dst exists, if it does this is a no-op
-	if err := os.MkdirAll(dst, info.Mode()); err != nil {
+func copyDirContents(src, dst string, info os.FileInfo, opts CopyDirContentsOptions) error {
+	if opts.DryRun != nil {
+		fmt.Fprintf(opts.DryRun, "create directory %s\n", dst)
+	} else if err := os.MkdirAll(dst, info.Mode()); err != nil {
+		// Make sure dst exists, if it does this is a no-op
 		return fmt.Errorf("failed to create directory %q: %w", dst, err)
 	}
 
@@ -125,7 +295,7 @@ func copyDirContents(src, dst string, info os.FileInfo) error {
 		}
 
 		if item.IsDir() {
-			err := copyDirContents(srcItemPath, dstItemPath, fi)
+			err := copyDirContents(srcItemPath, dstItemPath, fi, opts)
 			if err != nil {
 				return fmt.Errorf("failed to copy directory %q: %w", srcItemPath, err)
 			}
@@ -133,15 +303,169 @@ func copyDirContents(src, dst string, info os.FileInfo) error {
 		}
 		if !fi.Mode().IsRegular() {
 			// Unsupported file type, ignore
+			if opts.OnSkip != nil {
+				opts.OnSkip(srcItemPath, fmt.Sprintf("unsupported file type %v", fi.Mode().Type()))
+			}
 			continue
 		}
-		if err := copyFile(srcItemPath, dstItemPath, fi); err != nil {
+		if err := copyFile(srcItemPath, dstItemPath, fi, CopyFileOptions{DryRun: opts.DryRun}); err != nil {
 			return fmt.Errorf("failed to copy file %q: %w", srcItemPath, err)
 		}
 	}
 	return nil
 }
 
+// progressReader is the io.Reader returned by ProgressReader.
+type progressReader struct {
+	r          io.Reader
+	total      int64
+	read       int64
+	onProgress func(read, total int64)
+}
+
+// ProgressReader returns an io.Reader that wraps r and invokes onProgress with
+// the cumulative number of bytes read after each Read. total is the expected
+// total number of bytes and is passed through to onProgress; it may be -1 or
+// zero if unknown. This is a composable primitive for reporting progress of
+// any streaming operation, e.g. a download: wrap the source reader and update
+// a spinner from the callback.
+func ProgressReader(r io.Reader, total int64, onProgress func(read, total int64)) io.Reader {
+	return &progressReader{r: r, total: total, onProgress: onProgress}
+}
+
+func (pr *progressReader) Read(p []byte) (int, error) {
+	n, err := pr.r.Read(p)
+	if n > 0 {
+		pr.read += int64(n)
+		if pr.onProgress != nil {
+			pr.onProgress(pr.read, pr.total)
+		}
+	}
+	return n, err
+}
+
+// WriteJSON writes v to the file at path as pretty-printed JSON. Any intermediate
+// directories in path that do not exist will be created. The write is atomic,
+// the data is first written to a temporary file which is then renamed to path,
+// so a crash mid-write cannot leave a partially written file.
+func WriteJSON(path string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON for %q: %w", path, err)
+	}
+	data = append(data, '\n')
+
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, mkdirDefaultPerms); err != nil {
+		return fmt.Errorf("failed to create directory %q: %w", dir, err)
+	}
+	// Write to a temp file in the same directory then rename it so the
+	// write is atomic.
+	f, err := os.CreateTemp(dir, filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return fmt.Errorf("failed writing data to file %q: %w", f.Name(), err)
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return fmt.Errorf("failed to close file %q: %w", f.Name(), err)
+	}
+	if err := os.Rename(f.Name(), path); err != nil {
+		os.Remove(f.Name())
+		return fmt.Errorf("failed to rename %q to %q: %w", f.Name(), path, err)
+	}
+	return nil
+}
+
+// ReadJSON reads the file at path and unmarshals its contents into v.
+func ReadJSON(path string, v any) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read file %q: %w", path, err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to unmarshal JSON from %q: %w", path, err)
+	}
+	return nil
+}
+
+// TempFile creates a new temporary file in the directory dir using os.CreateTemp.
+// It returns the open file along with a cleanup function that closes and removes
+// the file. The cleanup function may be called multiple times, subsequent calls
+// are no-ops.
+//
+//	f, cleanup, err := file.TempFile("", "download-*")
+//	if err != nil {
+//		return err
+//	}
+//	defer cleanup()
+func TempFile(dir, pattern string) (*os.File, func() error, error) {
+	f, err := os.CreateTemp(dir, pattern)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create temp file: %w", err)
+	}
+	var once sync.Once
+	cleanup := func() error {
+		var err error
+		once.Do(func() {
+			closeErr := f.Close()
+			err = os.Remove(f.Name())
+			if err == nil {
+				err = closeErr
+			}
+		})
+		return err
+	}
+	return f, cleanup, nil
+}
+
+// TempFileContext is like TempFile but additionally removes the file when
+// ctx is cancelled. This is useful for long operations to ensure the file
+// does not leak if the operation is abandoned. The cleanup function should
+// still be called when the file is no longer needed.
+func TempFileContext(ctx context.Context, dir, pattern string) (*os.File, func() error, error) {
+	f, cleanup, err := TempFile(dir, pattern)
+	if err != nil {
+		return nil, nil, err
+	}
+	stop := context.AfterFunc(ctx, func() {
+		// Ignore error because there's nothing we can really do about it.
+		_ = cleanup()
+	})
+	return f, func() error {
+		stop()
+		return cleanup()
+	}, nil
+}
+
+// InDir changes the current working directory to dir, runs fn, and then
+// restores the original working directory, even if fn returns an error or
+// panics. This is handy for invoking tools that assume they run in a
+// specific directory.
+//
+// Since the working directory is process-global state, InDir is not safe to
+// use concurrently with other goroutines that depend on or change the
+// working directory.
+func InDir(dir string, fn func() error) error {
+	prev, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		return fmt.Errorf("failed to change directory to %q: %w", dir, err)
+	}
+	defer func() {
+		// There's not much that can be done if restoring fails, and returning
+		// an error here would mask fn's error, so let the chips fall.
+		_ = os.Chdir(prev)
+	}()
+	return fn()
+}
+
 // DirSize returns the size of the directory located at path.
 func DirSize(path string) (int64, error) {
 	s, err := os.Stat(path)
@@ -181,26 +505,172 @@ func DirLen(path string) (int, error) {
 // Note that Untar will overwrite any existing files with the same path
 // as files in the archive.
 func Untar(dir string, r io.Reader) error {
+	return UntarWithOptions(dir, r, UntarOptions{})
+}
+
+// ExistingPolicy controls how Untar handles an archive entry whose
+// destination path already exists.
+type ExistingPolicy int
+
+const (
+	// ExistingOverwrite overwrites the existing file. This is the default.
+	ExistingOverwrite ExistingPolicy = iota
+	// ExistingSkip leaves the existing file in place and does not extract the entry.
+	ExistingSkip
+	// ExistingFail returns an error.
+	ExistingFail
+)
+
+// UntarOptions is used to customize how UntarWithOptions behaves.
+// All fields are optional.
+type UntarOptions struct {
+	// PreserveOwner preserves the user and group ownership of extracted files
+	// using the IDs recorded in the tar headers. This generally requires running
+	// as root; if the process lacks the permission to change ownership it is
+	// silently skipped. Ownership is only supported on Unix-like platforms,
+	// on other platforms this option is ignored.
+	PreserveOwner bool
+	// DryRun causes the extraction to be skipped. The archive is still read
+	// and validated, but instead of writing to the filesystem the actions that
+	// would have been performed are written to DryRun.
+	DryRun io.Writer
+	// Existing controls what happens when a file or symlink in the archive
+	// already exists at the destination path. By default it is overwritten.
+	// The policy does not apply to directories, which are always reused.
+	Existing ExistingPolicy
+}
+
+// UntarWithOptions is like Untar but allows customizing the behaviour
+// of the extraction using opts.
+func UntarWithOptions(dir string, r io.Reader, opts UntarOptions) error {
+	return untar(dir, r, opts, nil)
+}
+
+// UntarFile opens the archive at archivePath and extracts it to dir.
+// It is a convenience wrapper around Untar that handles opening and closing
+// the file. Gzip compression is detected from the file contents, so both
+// .tar and .tar.gz/.tgz archives work regardless of extension.
+func UntarFile(dir, archivePath string) error {
+	f, err := os.Open(archivePath)
+	if err != nil {
+		return fmt.Errorf("failed to open archive %q: %w", archivePath, err)
+	}
+	defer f.Close()
+	return Untar(dir, f)
+}
+
+// UntarFunc is like Untar but only extracts entries for which fn returns true.
+// This enables selective extraction, e.g. pulling a single file out of an
+// archive without extracting the rest.
+func UntarFunc(dir string, r io.Reader, fn func(header *tar.Header) bool) error {
+	return untar(dir, r, UntarOptions{}, fn)
+}
+
+// newTarReader wraps r in a tar.Reader, transparently handling gzip
+// compression. If a gzip reader was created, it is returned as the closer and
+// must be closed by the caller once reading is complete.
+// A nil tar.Reader with a nil error means the input was empty.
+func newTarReader(r io.Reader) (*tar.Reader, io.Closer, error) {
 	// Determine if we are dealing with a gzip-compressed tar file.
 	// gzip files are identified by the first 3 bytes.
 	// See section 2.3.1. of RFC 1952: https://www.ietf.org/rfc/rfc1952.txt
 	buf := make([]byte, 3)
-	if _, err := io.ReadFull(r, buf); err != nil {
-		return fmt.Errorf("unable to check if tar file is gzip-compressed: %w", err)
+	n, err := io.ReadFull(r, buf)
+	if err == io.EOF {
+		// Empty input, treat it as an empty archive with nothing to extract.
+		return nil, nil, nil
+	} else if err == io.ErrUnexpectedEOF {
+		return nil, nil, fmt.Errorf("untar: archive too short to be a valid tar file: only %d bytes", n)
+	} else if err != nil {
+		return nil, nil, fmt.Errorf("unable to check if tar file is gzip-compressed: %w", err)
 	}
 
 	// Need to create a new reader with the 3 bytes added back to move back to the
 	// start of the file. Can do this by concatenating buf with r.
 	rr := io.MultiReader(bytes.NewReader(buf), r)
+	var closer io.Closer
 	if buf[0] == 0x1f && buf[1] == 0x8b && buf[2] == 8 {
 		gzr, err := gzip.NewReader(rr)
 		if err != nil {
-			return fmt.Errorf("unable to read gzip-compressed tar file: %w", err)
+			return nil, nil, fmt.Errorf("unable to read gzip-compressed tar file: %w", err)
 		}
-		defer gzr.Close()
+		closer = gzr
 		rr = gzr
 	}
-	tr := tar.NewReader(rr)
+	return tar.NewReader(rr), closer, nil
+}
+
+// TarEntry describes a single entry in a tar archive as reported by TarEntries.
+type TarEntry struct {
+	// Name is the path of the entry within the archive.
+	Name string
+	// Size is the size of the entry's contents in bytes.
+	Size int64
+	// Mode is the file mode of the entry, including the file type bits.
+	Mode os.FileMode
+	// Linkname is the target of the link if the entry is a symlink.
+	Linkname string
+	// Err is set if an error occurred while reading the archive.
+	// If Err is non-nil it will be the last entry sent on the channel
+	// and all other fields will be zero values.
+	Err error
+}
+
+// TarEntries reads the tar file from r and returns a channel that yields
+// metadata for each entry in the archive without extracting anything.
+// It can handle gzip-compressed tar files, same as Untar.
+//
+// The channel is closed once all entries have been read. If an error occurs
+// partway through the archive, it is delivered as a final TarEntry with Err set.
+// The caller must drain the channel to avoid leaking the reading goroutine.
+func TarEntries(r io.Reader) (<-chan TarEntry, error) {
+	tr, closer, err := newTarReader(r)
+	if err != nil {
+		return nil, err
+	}
+	ch := make(chan TarEntry)
+	go func() {
+		defer close(ch)
+		if closer != nil {
+			defer closer.Close()
+		}
+		if tr == nil {
+			// Empty input, no entries.
+			return
+		}
+		for {
+			header, err := tr.Next()
+			if err == io.EOF {
+				return
+			} else if err != nil {
+				ch <- TarEntry{Err: fmt.Errorf("untar: read error: %w", err)}
+				return
+			}
+			ch <- TarEntry{
+				Name:     header.Name,
+				Size:     header.Size,
+				Mode:     header.FileInfo().Mode(),
+				Linkname: header.Linkname,
+			}
+		}
+	}()
+	return ch, nil
+}
+
+// untar is the actual implementation of UntarWithOptions and UntarFunc.
+// If filter is non-nil, entries for which it returns false are skipped.
+func untar(dir string, r io.Reader, opts UntarOptions, filter func(*tar.Header) bool) error {
+	tr, closer, err := newTarReader(r)
+	if err != nil {
+		return err
+	}
+	if tr == nil {
+		// Empty input, nothing to extract.
+		return nil
+	}
+	if closer != nil {
+		defer closer.Close()
+	}
 
 	// Now we get to the fun part, the actual tar extraction.
 	// Loop through each entry in the archive and extract it.
@@ -214,6 +684,9 @@ func Untar(dir string, r io.Reader) error {
 		} else if err != nil {
 			return fmt.Errorf("untar: read error: %w", err)
 		}
+		if filter != nil && !filter(header) {
+			continue
+		}
 
 		dst := filepath.Join(dir, header.Name)
 		// Ensure the parent directory exists. Usually this shouldn't be required since there
@@ -221,7 +694,7 @@ func Untar(dir string, r io.Reader) error {
 		// However, testing has revealed that this is not always the case and there can be
 		// tar files without directory entries so we should handle those cases.
 		parentDir := filepath.Dir(dst)
-		if _, ok := madeDirs[parentDir]; !ok {
+		if _, ok := madeDirs[parentDir]; !ok && opts.DryRun == nil {
 			if err := os.MkdirAll(parentDir, mkdirDefaultPerms); err != nil {
 				return fmt.Errorf("untar: create directory error: %w", err)
 			}
@@ -229,6 +702,30 @@ func Untar(dir string, r io.Reader) error {
 		}
 
 		mode := header.FileInfo().Mode()
+		if opts.Existing != ExistingOverwrite && (mode.IsRegular() || mode&os.ModeSymlink != 0) {
+			// Use Lstat so an existing symlink counts as existing
+			// instead of checking what it points to.
+			if _, err := os.Lstat(dst); err == nil {
+				if opts.Existing == ExistingFail {
+					return fmt.Errorf("untar: %s already exists", dst)
+				}
+				// ExistingSkip, leave the existing file alone.
+				continue
+			}
+		}
+		if opts.DryRun != nil {
+			switch {
+			case mode.IsDir():
+				fmt.Fprintf(opts.DryRun, "create directory %s\n", dst)
+			case mode.IsRegular():
+				fmt.Fprintf(opts.DryRun, "extract file %s\n", dst)
+			case mode&os.ModeSymlink != 0:
+				fmt.Fprintf(opts.DryRun, "create symlink %s -> %s\n", dst, header.Linkname)
+			default:
+				return fmt.Errorf("tar file entry %s has unsupported file type %v", header.Name, mode)
+			}
+			continue
+		}
 		switch {
 		case mode.IsDir():
 			if err := os.MkdirAll(dst, mkdirDefaultPerms); err != nil {
@@ -257,12 +754,26 @@ func Untar(dir string, r io.Reader) error {
 				return fmt.Errorf("untar: only wrote %d bytes to %s; expected %d", n, dst, header.Size)
 			}
 		case mode&os.ModeSymlink != 0:
-			// Entry is a symlink, need to create a symlink to the target
-			if err := os.Symlink(header.Linkname, dst); err != nil {
+			// Entry is a symlink, need to create a symlink to the target.
+			// os.Symlink fails if the path already exists, so to overwrite we
+			// need to remove the existing file and retry.
+			err := os.Symlink(header.Linkname, dst)
+			if errors.Is(err, os.ErrExist) {
+				if err = os.Remove(dst); err == nil {
+					err = os.Symlink(header.Linkname, dst)
+				}
+			}
+			if err != nil {
 				return fmt.Errorf("untar: symlink error: %w", err)
 			}
 		default:
 			return fmt.Errorf("tar file entry %s has unsupported file type %v", header.Name, mode)
 		}
+
+		if opts.PreserveOwner {
+			if err := lchown(dst, header.Uid, header.Gid); err != nil {
+				return fmt.Errorf("untar: failed to change ownership of %s: %w", dst, err)
+			}
+		}
 	}
 }
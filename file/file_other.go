@@ -0,0 +1,15 @@
+//go:build !unix
+
+package file
+
+import "os"
+
+// owner is not supported on this platform, ok is always false.
+func owner(info os.FileInfo) (uid, gid int, ok bool) {
+	return 0, 0, false
+}
+
+// lchown is not supported on this platform and is a no-op.
+func lchown(path string, uid, gid int) error {
+	return nil
+}
@@ -1,11 +1,19 @@
 package file_test
 
 import (
+	"archive/tar"
+	"bytes"
+	"context"
 	"errors"
+	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
+	"syscall"
 	"testing"
+	"time"
 
 	"github.com/cszatmary/goutils/file"
 )
@@ -82,6 +90,43 @@ func TestCopyFileNotRegularFile(t *testing.T) {
 	}
 }
 
+func TestCopyFileContents(t *testing.T) {
+	tmpdir := t.TempDir()
+	src := filepath.Join(tmpdir, "src")
+	const content = `this is some file content`
+	err := os.WriteFile(src, []byte(content), 0o644)
+	if err != nil {
+		t.Fatalf("failed to write file %v", err)
+	}
+
+	var buf bytes.Buffer
+	n, err := file.CopyFileContents(&buf, src)
+	if err != nil {
+		t.Errorf("want nil error, got %v", err)
+	}
+	if n != int64(len(content)) {
+		t.Errorf("got %d bytes written, want %d", n, len(content))
+	}
+	if got := buf.String(); got != content {
+		t.Errorf("got contents %q, want %q", got, content)
+	}
+}
+
+func TestCopyFileContentsNotRegularFile(t *testing.T) {
+	tmpdir := t.TempDir()
+	src := filepath.Join(tmpdir, "src")
+	err := os.MkdirAll(src, 0o755)
+	if err != nil {
+		t.Fatalf("failed to create dir %v", err)
+	}
+
+	var buf bytes.Buffer
+	_, err = file.CopyFileContents(&buf, src)
+	if !errors.Is(err, file.ErrNotRegularFile) {
+		t.Errorf("got %v err, want %v", err, file.ErrNotRegularFile)
+	}
+}
+
 func TestCopyDirContents(t *testing.T) {
 	tmpdir := t.TempDir()
 	src := filepath.Join(tmpdir, "src")
@@ -109,6 +154,73 @@ func TestCopyDirContents(t *testing.T) {
 	assertFile(t, filepath.Join(dst, "foodir", "bazfile"), bazfileContent)
 }
 
+func TestCopyDirContentsEmptyDir(t *testing.T) {
+	tmpdir := t.TempDir()
+	src := filepath.Join(tmpdir, "src")
+	dst := filepath.Join(tmpdir, "dst")
+	// A directory tree with an empty subdirectory and a nested one that only
+	// contains another empty directory. Both must be recreated in dst.
+	err := os.MkdirAll(filepath.Join(src, "emptydir"), 0o755)
+	if err != nil {
+		t.Fatalf("failed to create dir: %s", err)
+	}
+	err = os.MkdirAll(filepath.Join(src, "cache", "objects"), 0o755)
+	if err != nil {
+		t.Fatalf("failed to create dir: %s", err)
+	}
+
+	err = file.CopyDirContents(src, dst)
+	if err != nil {
+		t.Errorf("want nil error, got %v", err)
+	}
+	for _, dir := range []string{"emptydir", "cache/objects"} {
+		path := filepath.Join(dst, dir)
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatalf("want %s to exist, got %v", path, err)
+		}
+		if !info.IsDir() {
+			t.Errorf("want %s to be a directory", path)
+		}
+	}
+}
+
+func TestCopyDirContentsOnSkip(t *testing.T) {
+	tmpdir := t.TempDir()
+	src := filepath.Join(tmpdir, "src")
+	dst := filepath.Join(tmpdir, "dst")
+	err := os.MkdirAll(src, 0o755)
+	if err != nil {
+		t.Fatalf("failed to create dir: %s", err)
+	}
+	const barfileContent = "bar"
+	err = os.WriteFile(filepath.Join(src, "barfile"), []byte(barfileContent), 0o644)
+	if err != nil {
+		t.Fatalf("failed to create file: %s", err)
+	}
+	fifoPath := filepath.Join(src, "fifo")
+	if err := syscall.Mkfifo(fifoPath, 0o644); err != nil {
+		t.Skipf("unable to create named pipe: %v", err)
+	}
+
+	var skipped []string
+	err = file.CopyDirContentsWithOptions(src, dst, file.CopyDirContentsOptions{
+		OnSkip: func(path string, reason string) {
+			skipped = append(skipped, path+": "+reason)
+		},
+	})
+	if err != nil {
+		t.Errorf("want nil error, got %v", err)
+	}
+	assertFile(t, filepath.Join(dst, "barfile"), barfileContent)
+	if len(skipped) != 1 {
+		t.Fatalf("got %d skipped entries, want 1", len(skipped))
+	}
+	if !strings.HasPrefix(skipped[0], fifoPath+": unsupported file type") {
+		t.Errorf("got skipped entry %q, want it to report %q", skipped[0], fifoPath)
+	}
+}
+
 func TestCopyDirContentsNotDir(t *testing.T) {
 	tmpdir := t.TempDir()
 	src := filepath.Join(tmpdir, "src")
@@ -229,6 +341,226 @@ func TestUntar(t *testing.T) {
 	}
 }
 
+func TestUntarFile(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+	}{
+		{"normal tar file", "testdata/basic.tar"},
+		{"gzip-compressed tar file", "testdata/basic.tgz"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpdir := t.TempDir()
+			err := file.UntarFile(tmpdir, tt.path)
+			if err != nil {
+				t.Fatalf("want nil error, got %v", err)
+			}
+
+			assertFile(t, filepath.Join(tmpdir, "a.txt"), "This is a file\n")
+			assertFile(t, filepath.Join(tmpdir, "b/c.txt"), "This is another file inside a directory\n")
+		})
+	}
+}
+
+func TestUntarFileNotExist(t *testing.T) {
+	err := file.UntarFile(t.TempDir(), "testdata/notanarchive.tar")
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("got %v err, want %v", err, os.ErrNotExist)
+	}
+}
+
+func TestTarEntries(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+	}{
+		{"normal tar file", "testdata/basic.tar"},
+		{"gzip-compressed tar file", "testdata/basic.tgz"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := os.Open(tt.path)
+			if err != nil {
+				t.Fatalf("failed to open %s: %v", tt.path, err)
+			}
+			t.Cleanup(func() {
+				f.Close()
+			})
+
+			ch, err := file.TarEntries(f)
+			if err != nil {
+				t.Fatalf("want nil error, got %v", err)
+			}
+			var entries []file.TarEntry
+			for e := range ch {
+				if e.Err != nil {
+					t.Fatalf("want nil entry error, got %v", e.Err)
+				}
+				entries = append(entries, e)
+			}
+
+			want := []file.TarEntry{
+				{Name: "a.txt", Size: 15},
+				{Name: "b/"},
+				{Name: "b/c.txt", Size: 40},
+			}
+			if len(entries) != len(want) {
+				t.Fatalf("got %d entries, want %d", len(entries), len(want))
+			}
+			for i, w := range want {
+				e := entries[i]
+				if e.Name != w.Name {
+					t.Errorf("got entry name %q, want %q", e.Name, w.Name)
+				}
+				if e.Size != w.Size {
+					t.Errorf("got entry size %d, want %d", e.Size, w.Size)
+				}
+			}
+			if !entries[1].Mode.IsDir() {
+				t.Errorf("got mode %v for %s, want a directory", entries[1].Mode, entries[1].Name)
+			}
+			if !entries[2].Mode.IsRegular() {
+				t.Errorf("got mode %v for %s, want a regular file", entries[2].Mode, entries[2].Name)
+			}
+		})
+	}
+}
+
+func TestUntarFunc(t *testing.T) {
+	f, err := os.Open("testdata/basic.tar")
+	if err != nil {
+		t.Fatalf("failed to open testdata/basic.tar: %v", err)
+	}
+	t.Cleanup(func() {
+		f.Close()
+	})
+
+	tmpdir := t.TempDir()
+	err = file.UntarFunc(tmpdir, f, func(header *tar.Header) bool {
+		return header.Name == "b/c.txt"
+	})
+	if err != nil {
+		t.Fatalf("want nil error, got %v", err)
+	}
+
+	assertFile(t, filepath.Join(tmpdir, "b/c.txt"), "This is another file inside a directory\n")
+	if file.Exists(filepath.Join(tmpdir, "a.txt")) {
+		t.Error("a.txt was extracted, want it skipped")
+	}
+}
+
+func TestUntarShortInput(t *testing.T) {
+	t.Run("empty input", func(t *testing.T) {
+		// An empty input is treated as an empty archive, nothing to extract.
+		tmpdir := t.TempDir()
+		err := file.Untar(tmpdir, strings.NewReader(""))
+		if err != nil {
+			t.Fatalf("want nil error, got %v", err)
+		}
+		n, err := file.DirLen(tmpdir)
+		if err != nil {
+			t.Fatalf("failed to get dir len: %v", err)
+		}
+		if n != 0 {
+			t.Errorf("got dir len %d, want 0", n)
+		}
+	})
+	t.Run("truncated input", func(t *testing.T) {
+		tmpdir := t.TempDir()
+		err := file.Untar(tmpdir, strings.NewReader("\x1f"))
+		if err == nil {
+			t.Fatal("expected an error, got nil")
+		}
+		if !strings.Contains(err.Error(), "too short") {
+			t.Errorf("got error %q, want it to mention the archive being too short", err)
+		}
+	})
+}
+
+func TestDryRun(t *testing.T) {
+	t.Run("copy file", func(t *testing.T) {
+		tmpdir := t.TempDir()
+		src := filepath.Join(tmpdir, "src")
+		dst := filepath.Join(tmpdir, "dst")
+		err := os.WriteFile(src, []byte("content"), 0o644)
+		if err != nil {
+			t.Fatalf("failed to write file %v", err)
+		}
+
+		var buf bytes.Buffer
+		err = file.CopyFileWithOptions(src, dst, file.CopyFileOptions{DryRun: &buf})
+		if err != nil {
+			t.Fatalf("want nil error, got %v", err)
+		}
+		if file.Exists(dst) {
+			t.Errorf("want %s to not exist, but it does", dst)
+		}
+		want := "copy file " + src + " to " + dst + "\n"
+		if got := buf.String(); got != want {
+			t.Errorf("got actions\n\t%s\nwant\n\t%s", got, want)
+		}
+	})
+	t.Run("copy dir contents", func(t *testing.T) {
+		tmpdir := t.TempDir()
+		src := filepath.Join(tmpdir, "src")
+		dst := filepath.Join(tmpdir, "dst")
+		err := os.MkdirAll(filepath.Join(src, "foodir"), 0o755)
+		if err != nil {
+			t.Fatalf("failed to create dir: %s", err)
+		}
+		err = os.WriteFile(filepath.Join(src, "barfile"), []byte("bar"), 0o644)
+		if err != nil {
+			t.Fatalf("failed to create file: %s", err)
+		}
+
+		var buf bytes.Buffer
+		err = file.CopyDirContentsWithOptions(src, dst, file.CopyDirContentsOptions{DryRun: &buf})
+		if err != nil {
+			t.Fatalf("want nil error, got %v", err)
+		}
+		if file.Exists(dst) {
+			t.Errorf("want %s to not exist, but it does", dst)
+		}
+		want := "create directory " + dst + "\n" +
+			"copy file " + filepath.Join(src, "barfile") + " to " + filepath.Join(dst, "barfile") + "\n" +
+			"create directory " + filepath.Join(dst, "foodir") + "\n"
+		if got := buf.String(); got != want {
+			t.Errorf("got actions\n\t%s\nwant\n\t%s", got, want)
+		}
+	})
+	t.Run("untar", func(t *testing.T) {
+		const path = "testdata/basic.tar"
+		f, err := os.Open(path)
+		if err != nil {
+			t.Fatalf("failed to open %s: %v", path, err)
+		}
+		t.Cleanup(func() {
+			f.Close()
+		})
+
+		var buf bytes.Buffer
+		tmpdir := t.TempDir()
+		err = file.UntarWithOptions(tmpdir, f, file.UntarOptions{DryRun: &buf})
+		if err != nil {
+			t.Fatalf("want nil error, got %v", err)
+		}
+		n, err := file.DirLen(tmpdir)
+		if err != nil {
+			t.Fatalf("failed to get dir len: %v", err)
+		}
+		if n != 0 {
+			t.Errorf("got dir len %d, want 0", n)
+		}
+		got := buf.String()
+		for _, want := range []string{"extract file " + filepath.Join(tmpdir, "a.txt")} {
+			if !strings.Contains(got, want) {
+				t.Errorf("got actions %q, want to contain %q", got, want)
+			}
+		}
+	})
+}
+
 func TestUntarSymlink(t *testing.T) {
 	const path = "testdata/basic_symlink.tgz"
 	f, err := os.Open(path)
@@ -270,3 +602,312 @@ func assertFile(t *testing.T, path, want string) {
 		t.Errorf("got %q, want %q", got, want)
 	}
 }
+
+func TestTempFile(t *testing.T) {
+	f, cleanup, err := file.TempFile(t.TempDir(), "test-*")
+	if err != nil {
+		t.Fatalf("want nil error, got %v", err)
+	}
+	if !file.Exists(f.Name()) {
+		t.Fatalf("want %s to exist, but it doesn't", f.Name())
+	}
+
+	if err := cleanup(); err != nil {
+		t.Errorf("want nil error, got %v", err)
+	}
+	if file.Exists(f.Name()) {
+		t.Errorf("want %s to be removed, but it exists", f.Name())
+	}
+	// Calling cleanup again must be a no-op.
+	if err := cleanup(); err != nil {
+		t.Errorf("want nil error, got %v", err)
+	}
+}
+
+func TestTempFileContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	f, cleanup, err := file.TempFileContext(ctx, t.TempDir(), "test-*")
+	if err != nil {
+		t.Fatalf("want nil error, got %v", err)
+	}
+	if !file.Exists(f.Name()) {
+		t.Fatalf("want %s to exist, but it doesn't", f.Name())
+	}
+
+	cancel()
+	// The removal happens on a separate goroutine, poll for it.
+	removed := false
+	for i := 0; i < 100; i++ {
+		if !file.Exists(f.Name()) {
+			removed = true
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if !removed {
+		t.Errorf("want %s to be removed after cancellation, but it exists", f.Name())
+	}
+	if err := cleanup(); err != nil {
+		t.Errorf("want nil error, got %v", err)
+	}
+}
+
+func TestWriteReadJSON(t *testing.T) {
+	type state struct {
+		Name    string `json:"name"`
+		Version int    `json:"version"`
+	}
+	tmpdir := t.TempDir()
+	// Use a nested path to make sure intermediate directories are created.
+	path := filepath.Join(tmpdir, "nested", "state.json")
+	want := state{Name: "foo", Version: 2}
+	if err := file.WriteJSON(path, want); err != nil {
+		t.Fatalf("want nil error, got %v", err)
+	}
+
+	var got state
+	if err := file.ReadJSON(path, &got); err != nil {
+		t.Fatalf("want nil error, got %v", err)
+	}
+	if got != want {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestReadJSONMalformed(t *testing.T) {
+	tmpdir := t.TempDir()
+	path := filepath.Join(tmpdir, "state.json")
+	if err := os.WriteFile(path, []byte("{not json"), 0o644); err != nil {
+		t.Fatalf("failed to write file %v", err)
+	}
+
+	var v map[string]any
+	err := file.ReadJSON(path, &v)
+	if err == nil {
+		t.Fatal("expected an error, got nil")
+	}
+	if !strings.Contains(err.Error(), "unmarshal") {
+		t.Errorf("got error %q, want it to mention unmarshalling", err)
+	}
+}
+
+func TestDownloadAll(t *testing.T) {
+	tmpdir := t.TempDir()
+	contents := map[string]string{
+		"a.txt": "alpha",
+		"b.txt": "beta",
+		"c.txt": "gamma",
+	}
+	var items []file.DownloadItem
+	for name, data := range contents {
+		data := data
+		items = append(items, file.DownloadItem{
+			Open: func() (io.ReadCloser, error) {
+				return io.NopCloser(strings.NewReader(data)), nil
+			},
+			Dst: filepath.Join(tmpdir, name),
+		})
+	}
+
+	var mu sync.Mutex
+	completed := make(map[string]int64)
+	err := file.DownloadAll(context.Background(), items, file.DownloadAllOptions{
+		Concurrency: 2,
+		OnComplete: func(item file.DownloadItem, n int64) {
+			mu.Lock()
+			defer mu.Unlock()
+			completed[filepath.Base(item.Dst)] = n
+		},
+	})
+	if err != nil {
+		t.Fatalf("want nil error, got %v", err)
+	}
+	for name, want := range contents {
+		data, err := os.ReadFile(filepath.Join(tmpdir, name))
+		if err != nil {
+			t.Fatalf("failed to read downloaded file: %v", err)
+		}
+		if string(data) != want {
+			t.Errorf("got %q, want %q", data, want)
+		}
+		if completed[name] != int64(len(want)) {
+			t.Errorf("got %d bytes reported for %s, want %d", completed[name], name, len(want))
+		}
+	}
+}
+
+func TestDownloadAllError(t *testing.T) {
+	tmpdir := t.TempDir()
+	items := []file.DownloadItem{
+		{
+			Open: func() (io.ReadCloser, error) {
+				return nil, errors.New("connection refused")
+			},
+			Dst: filepath.Join(tmpdir, "bad.txt"),
+		},
+		{
+			Open: func() (io.ReadCloser, error) {
+				return io.NopCloser(strings.NewReader("ok")), nil
+			},
+			Dst: filepath.Join(tmpdir, "good.txt"),
+		},
+	}
+	err := file.DownloadAll(context.Background(), items, file.DownloadAllOptions{})
+	if err == nil {
+		t.Fatal("want error, got nil")
+	}
+	wantMsg := fmt.Sprintf("failed to open source for %q: connection refused", items[0].Dst)
+	if err.Error() != wantMsg {
+		t.Errorf("got %q, want %q", err.Error(), wantMsg)
+	}
+	// The other download should still have completed.
+	if !file.Exists(filepath.Join(tmpdir, "good.txt")) {
+		t.Error("want good.txt to exist")
+	}
+}
+
+func TestUntarExistingPolicy(t *testing.T) {
+	untarWith := func(t *testing.T, policy file.ExistingPolicy, dir string) error {
+		f, err := os.Open("testdata/basic.tar")
+		if err != nil {
+			t.Fatalf("failed to open testdata/basic.tar: %v", err)
+		}
+		t.Cleanup(func() {
+			f.Close()
+		})
+		return file.UntarWithOptions(dir, f, file.UntarOptions{Existing: policy})
+	}
+	writeConflict := func(t *testing.T) string {
+		tmpdir := t.TempDir()
+		err := os.WriteFile(filepath.Join(tmpdir, "a.txt"), []byte("user modified\n"), 0o644)
+		if err != nil {
+			t.Fatalf("failed to write conflicting file: %v", err)
+		}
+		return tmpdir
+	}
+
+	t.Run("overwrite", func(t *testing.T) {
+		tmpdir := writeConflict(t)
+		if err := untarWith(t, file.ExistingOverwrite, tmpdir); err != nil {
+			t.Fatalf("want nil error, got %v", err)
+		}
+		assertFile(t, filepath.Join(tmpdir, "a.txt"), "This is a file\n")
+	})
+	t.Run("skip", func(t *testing.T) {
+		tmpdir := writeConflict(t)
+		if err := untarWith(t, file.ExistingSkip, tmpdir); err != nil {
+			t.Fatalf("want nil error, got %v", err)
+		}
+		assertFile(t, filepath.Join(tmpdir, "a.txt"), "user modified\n")
+		// Non-conflicting entries are still extracted.
+		assertFile(t, filepath.Join(tmpdir, "b/c.txt"), "This is another file inside a directory\n")
+	})
+	t.Run("fail", func(t *testing.T) {
+		tmpdir := writeConflict(t)
+		err := untarWith(t, file.ExistingFail, tmpdir)
+		if err == nil {
+			t.Fatal("expected an error, got nil")
+		}
+		if !strings.Contains(err.Error(), "already exists") {
+			t.Errorf("got error %q, want it to mention the file already existing", err)
+		}
+	})
+}
+
+func TestInDir(t *testing.T) {
+	prev, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("failed to get working directory: %v", err)
+	}
+	tmpdir := t.TempDir()
+
+	err = file.InDir(tmpdir, func() error {
+		wd, err := os.Getwd()
+		if err != nil {
+			return err
+		}
+		// Resolve symlinks since the temp dir may be behind one, e.g. on macOS.
+		want, err := filepath.EvalSymlinks(tmpdir)
+		if err != nil {
+			return err
+		}
+		if got, err := filepath.EvalSymlinks(wd); err != nil || got != want {
+			t.Errorf("got wd %q, want %q", wd, want)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("want nil error, got %v", err)
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("failed to get working directory: %v", err)
+	}
+	if wd != prev {
+		t.Errorf("got wd %q after InDir, want %q restored", wd, prev)
+	}
+}
+
+func TestInDirError(t *testing.T) {
+	prev, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("failed to get working directory: %v", err)
+	}
+
+	wantErr := fmt.Errorf("oops")
+	err = file.InDir(t.TempDir(), func() error {
+		return wantErr
+	})
+	if err != wantErr {
+		t.Errorf("got error %v, want %v", err, wantErr)
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("failed to get working directory: %v", err)
+	}
+	if wd != prev {
+		t.Errorf("got wd %q after InDir, want %q restored", wd, prev)
+	}
+}
+
+func TestProgressReader(t *testing.T) {
+	const content = "this is some content for the progress reader"
+	total := int64(len(content))
+	var reads []int64
+	r := file.ProgressReader(strings.NewReader(content), total, func(read, tot int64) {
+		if tot != total {
+			t.Errorf("got total %d, want %d", tot, total)
+		}
+		reads = append(reads, read)
+	})
+
+	// Read with a small buffer so multiple reads occur.
+	var buf bytes.Buffer
+	p := make([]byte, 10)
+	for {
+		n, err := r.Read(p)
+		buf.Write(p[:n])
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("want nil error, got %v", err)
+		}
+	}
+	if got := buf.String(); got != content {
+		t.Errorf("got contents %q, want %q", got, content)
+	}
+	if len(reads) < 2 {
+		t.Fatalf("got %d progress callbacks, want at least 2", len(reads))
+	}
+	for i := 1; i < len(reads); i++ {
+		if reads[i] <= reads[i-1] {
+			t.Errorf("got read count %d after %d, want increasing", reads[i], reads[i-1])
+		}
+	}
+	if last := reads[len(reads)-1]; last != total {
+		t.Errorf("got final read count %d, want %d", last, total)
+	}
+}
@@ -0,0 +1,30 @@
+//go:build unix
+
+package file
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// owner returns the user and group IDs that own the file described by info.
+// ok is false if the ownership could not be determined.
+func owner(info os.FileInfo) (uid, gid int, ok bool) {
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, 0, false
+	}
+	return int(st.Uid), int(st.Gid), true
+}
+
+// lchown changes the ownership of the file at path without following symlinks.
+// If the process does not have permission to change ownership (i.e. it is not
+// running as root), lchown is a no-op.
+func lchown(path string, uid, gid int) error {
+	err := os.Lchown(path, uid, gid)
+	if errors.Is(err, os.ErrPermission) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,87 @@
+//go:build unix
+
+package file_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/cszatmary/goutils/file"
+)
+
+// The uid/gid used when testing ownership preservation.
+// The actual value doesn't matter, it just needs to differ from root.
+const testOwnerID = 1234
+
+func TestCopyFilePreserveOwner(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("requires running as root")
+	}
+	tmpdir := t.TempDir()
+	src := filepath.Join(tmpdir, "src.txt")
+	if err := os.WriteFile(src, []byte("hello"), 0o644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	if err := os.Chown(src, testOwnerID, testOwnerID); err != nil {
+		t.Fatalf("failed to chown file: %v", err)
+	}
+
+	dst := filepath.Join(tmpdir, "dst.txt")
+	err := file.CopyFileWithOptions(src, dst, file.CopyFileOptions{PreserveOwner: true})
+	if err != nil {
+		t.Fatalf("want nil error, got %v", err)
+	}
+	assertOwner(t, dst, testOwnerID, testOwnerID)
+}
+
+func TestUntarPreserveOwner(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("requires running as root")
+	}
+	// Build a small tar archive with a known owner.
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	const contents = "This is a file\n"
+	err := tw.WriteHeader(&tar.Header{
+		Name: "a.txt",
+		Mode: 0o644,
+		Size: int64(len(contents)),
+		Uid:  testOwnerID,
+		Gid:  testOwnerID,
+	})
+	if err != nil {
+		t.Fatalf("failed to write tar header: %v", err)
+	}
+	if _, err := tw.Write([]byte(contents)); err != nil {
+		t.Fatalf("failed to write tar contents: %v", err)
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("failed to close tar writer: %v", err)
+	}
+
+	tmpdir := t.TempDir()
+	err = file.UntarWithOptions(tmpdir, &buf, file.UntarOptions{PreserveOwner: true})
+	if err != nil {
+		t.Fatalf("want nil error, got %v", err)
+	}
+	assertOwner(t, filepath.Join(tmpdir, "a.txt"), testOwnerID, testOwnerID)
+}
+
+func assertOwner(t *testing.T, path string, uid, gid int) {
+	t.Helper()
+	info, err := os.Lstat(path)
+	if err != nil {
+		t.Fatalf("failed to stat %s: %v", path, err)
+	}
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		t.Fatal("unable to get ownership info")
+	}
+	if int(st.Uid) != uid || int(st.Gid) != gid {
+		t.Errorf("got owner %d:%d, want %d:%d", st.Uid, st.Gid, uid, gid)
+	}
+}
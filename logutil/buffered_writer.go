@@ -0,0 +1,50 @@
+package logutil
+
+import (
+	"bufio"
+	"io"
+	"sync"
+)
+
+// BufferedWriter is an io.WriteCloser that buffers writes to an underlying
+// writer in order to reduce the number of small writes, e.g. syscalls when
+// writing to a file. It is intended to wrap a file before handing it to a
+// log handler.
+//
+// Data is flushed to the underlying writer once the buffer fills up, or
+// explicitly via Flush or Close. Callers must call Flush or Close when done
+// to ensure all buffered data is delivered.
+//
+// A BufferedWriter is safe for use by multiple goroutines. Note that handlers
+// already serialize their writes, so the locking only matters if the same
+// BufferedWriter is shared by multiple handlers.
+type BufferedWriter struct {
+	mu sync.Mutex
+	bw *bufio.Writer
+}
+
+// NewBufferedWriter creates a new BufferedWriter that writes to w with a
+// buffer of at least size bytes. If size is zero or negative, a default
+// size is used.
+func NewBufferedWriter(w io.Writer, size int) *BufferedWriter {
+	return &BufferedWriter{bw: bufio.NewWriterSize(w, size)}
+}
+
+func (b *BufferedWriter) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.bw.Write(p)
+}
+
+// Flush writes any buffered data to the underlying writer.
+func (b *BufferedWriter) Flush() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.bw.Flush()
+}
+
+// Close flushes any buffered data to the underlying writer.
+// The underlying writer is not closed, that is the caller's responsibility.
+func (b *BufferedWriter) Close() error {
+	return b.Flush()
+}
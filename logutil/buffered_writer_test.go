@@ -0,0 +1,73 @@
+package logutil_test
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/cszatmary/goutils/logutil"
+)
+
+// countingWriter counts the number of Write calls made to the underlying writer.
+type countingWriter struct {
+	w      io.Writer
+	writes int
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	cw.writes++
+	return cw.w.Write(p)
+}
+
+func TestBufferedWriter(t *testing.T) {
+	var buf bytes.Buffer
+	cw := &countingWriter{w: &buf}
+	w := logutil.NewBufferedWriter(cw, 4096)
+	logger := slog.New(slog.NewTextHandler(w, &slog.HandlerOptions{
+		ReplaceAttr: logutil.RemoveKeys(slog.TimeKey),
+	}))
+
+	for i := 0; i < 10; i++ {
+		logger.Info("a message", "i", i)
+	}
+	// All records fit in the buffer so nothing should have hit the
+	// underlying writer yet.
+	if cw.writes != 0 {
+		t.Errorf("got %d writes before close, want 0", cw.writes)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("failed to close writer: %v", err)
+	}
+	// Close must flush the buffered data in a single write.
+	if cw.writes != 1 {
+		t.Errorf("got %d writes after close, want 1", cw.writes)
+	}
+
+	lines := bytes.Count(buf.Bytes(), []byte("\n"))
+	if lines != 10 {
+		t.Errorf("got %d log lines, want 10", lines)
+	}
+}
+
+func BenchmarkBufferedWriter(b *testing.B) {
+	newLogger := func(w io.Writer) *slog.Logger {
+		return slog.New(slog.NewTextHandler(w, nil))
+	}
+	b.Run("unbuffered", func(b *testing.B) {
+		logger := newLogger(io.Discard)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			logger.Info("a message", "i", i)
+		}
+	})
+	b.Run("buffered", func(b *testing.B) {
+		w := logutil.NewBufferedWriter(io.Discard, 4096)
+		logger := newLogger(w)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			logger.Info("a message", "i", i)
+		}
+		w.Flush()
+	})
+}
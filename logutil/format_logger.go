@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"sync"
 	"time"
 
 	"github.com/cszatmary/goutils/progress"
@@ -20,6 +21,14 @@ func NewFormatLogger(h slog.Handler) *FormatLogger {
 	return &FormatLogger{slog.New(h)}
 }
 
+// TrackerLogger returns a progress.Logger that logs to the given handler.
+// It is a convenience for passing a bare slog.Handler to APIs that take a
+// progress.Logger without needing to know about FormatLogger. The returned
+// logger is a *FormatLogger, so Printf-style methods are available too.
+func TrackerLogger(h slog.Handler) progress.Logger {
+	return NewFormatLogger(h)
+}
+
 func (l *FormatLogger) WithAttrs(args ...any) progress.Logger {
 	return l.With(args...)
 }
@@ -31,6 +40,37 @@ func (l *FormatLogger) With(args ...any) *FormatLogger {
 	return &FormatLogger{l.Logger.With(args...)}
 }
 
+// ContextExtractor extracts log attributes from a context.
+// The returned args are key-value pairs as accepted by FormatLogger.With.
+type ContextExtractor func(ctx context.Context) []any
+
+var contextExtractors struct {
+	mu  sync.RWMutex
+	fns []ContextExtractor
+}
+
+// RegisterContextExtractor registers f to be called by FormatLogger.WithContext
+// to extract attributes from a context. Extractors are called in the order
+// they were registered.
+func RegisterContextExtractor(f ContextExtractor) {
+	contextExtractors.mu.Lock()
+	defer contextExtractors.mu.Unlock()
+	contextExtractors.fns = append(contextExtractors.fns, f)
+}
+
+// WithContext returns a FormatLogger that contains any attributes extracted from
+// ctx by the extractors registered with RegisterContextExtractor. This allows
+// request-scoped values such as trace or request IDs to be attached to logs
+// automatically.
+func (l *FormatLogger) WithContext(ctx context.Context) *FormatLogger {
+	contextExtractors.mu.RLock()
+	defer contextExtractors.mu.RUnlock()
+	for _, f := range contextExtractors.fns {
+		l = l.With(f(ctx)...)
+	}
+	return l
+}
+
 func (l *FormatLogger) WithGroup(name string) *FormatLogger {
 	if name == "" {
 		return l
@@ -38,6 +78,36 @@ func (l *FormatLogger) WithGroup(name string) *FormatLogger {
 	return &FormatLogger{l.Logger.WithGroup(name)}
 }
 
+// WithGroupAttrs returns a FormatLogger that contains the given attributes
+// namespaced under group. It is shorthand for WithGroup(group).With(args...).
+// The arguments are expected to be a set of key-value pairs, see With.
+func (l *FormatLogger) WithGroupAttrs(group string, args ...any) *FormatLogger {
+	return l.WithGroup(group).With(args...)
+}
+
+// Enabled reports whether the logger emits records at the given level.
+// It can be used by callers to guard expensive computations that are only
+// needed for logging. The formatted methods (Debugf, Infof, etc.) already
+// perform this check before calling Sprintf, so no formatting cost is paid
+// for disabled levels.
+func (l *FormatLogger) Enabled(level slog.Level) bool {
+	return l.Logger.Enabled(context.Background(), level)
+}
+
+// Log logs at the given level, which may be a custom level computed at runtime.
+// It implements the Log method of the progress.Logger interface.
+func (l *FormatLogger) Log(level slog.Level, msg string, args ...any) {
+	ctx := context.Background()
+	if !l.Logger.Enabled(ctx, level) {
+		return
+	}
+	// Calculate source, skip [CallerPC, this function]
+	pc := CallerPC(2)
+	r := slog.NewRecord(time.Now(), level, msg, pc)
+	r.Add(args...)
+	_ = l.Logger.Handler().Handle(ctx, r)
+}
+
 func (l *FormatLogger) Debugf(format string, args ...any) {
 	l.logf(slog.LevelDebug, format, args...)
 }
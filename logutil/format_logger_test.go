@@ -2,6 +2,8 @@ package logutil_test
 
 import (
 	"bytes"
+	"context"
+	"io"
 	"log/slog"
 	"path/filepath"
 	"strconv"
@@ -59,6 +61,13 @@ func TestFormatLogger(t *testing.T) {
 			},
 			want: `level=INFO msg="hello foo 20" std.bar=baz` + "\n",
 		},
+		{
+			name: "WithGroupAttrs-Infof",
+			do: func(l *logutil.FormatLogger) {
+				l.WithGroupAttrs("http", "method", "GET", "status", 200).Infof("hello %s %d", "foo", 20)
+			},
+			want: `level=INFO msg="hello foo 20" http.method=GET http.status=200` + "\n",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -99,6 +108,53 @@ func TestFormatLoggerSource(t *testing.T) {
 	}
 }
 
+func TestTrackerLogger(t *testing.T) {
+	var b bytes.Buffer
+	logger := logutil.TrackerLogger(slog.NewTextHandler(&b, &slog.HandlerOptions{
+		ReplaceAttr: logutil.RemoveKeys(slog.TimeKey),
+	}))
+	logger.Info("hello", "bar", "baz")
+	want := `level=INFO msg=hello bar=baz` + "\n"
+	if got := b.String(); got != want {
+		t.Errorf("\ngot\n\t%s\nwant\n\t%s", got, want)
+	}
+
+	// The returned logger should be a *FormatLogger so the Printf-style
+	// methods are available.
+	b.Reset()
+	fl, ok := logger.(*logutil.FormatLogger)
+	if !ok {
+		t.Fatalf("got %T, want *logutil.FormatLogger", logger)
+	}
+	fl.Infof("hello %s %d", "foo", 20)
+	want = `level=INFO msg="hello foo 20"` + "\n"
+	if got := b.String(); got != want {
+		t.Errorf("\ngot\n\t%s\nwant\n\t%s", got, want)
+	}
+}
+
+func TestFormatLoggerWithContext(t *testing.T) {
+	type requestIDKey struct{}
+	logutil.RegisterContextExtractor(func(ctx context.Context) []any {
+		id, ok := ctx.Value(requestIDKey{}).(string)
+		if !ok {
+			return nil
+		}
+		return []any{"request_id", id}
+	})
+
+	var b bytes.Buffer
+	logger := logutil.NewFormatLogger(slog.NewTextHandler(&b, &slog.HandlerOptions{
+		ReplaceAttr: logutil.RemoveKeys(slog.TimeKey),
+	}))
+	ctx := context.WithValue(context.Background(), requestIDKey{}, "abc123")
+	logger.WithContext(ctx).Infof("hello %s", "foo")
+	want := `level=INFO msg="hello foo" request_id=abc123` + "\n"
+	if got := b.String(); got != want {
+		t.Errorf("\ngot\n\t%s\nwant\n\t%s", got, want)
+	}
+}
+
 func TestFormatLoggerLevelDisabled(t *testing.T) {
 	var b bytes.Buffer
 	logger := logutil.NewFormatLogger(slog.NewTextHandler(&b, &slog.HandlerOptions{
@@ -109,3 +165,43 @@ func TestFormatLoggerLevelDisabled(t *testing.T) {
 		t.Errorf("\ngot\n\t%s\nwant empty string", got)
 	}
 }
+
+func TestFormatLoggerEnabled(t *testing.T) {
+	logger := logutil.NewFormatLogger(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{
+		Level: slog.LevelInfo,
+	}))
+	if logger.Enabled(slog.LevelDebug) {
+		t.Error("Enabled(debug) = true, want false")
+	}
+	if !logger.Enabled(slog.LevelInfo) {
+		t.Error("Enabled(info) = false, want true")
+	}
+	if !logger.Enabled(slog.LevelError) {
+		t.Error("Enabled(error) = false, want true")
+	}
+}
+
+func BenchmarkFormatLoggerDisabledDebugf(b *testing.B) {
+	// The level guard in logf should make a disabled Debugf avoid the
+	// Sprintf cost entirely.
+	logger := logutil.NewFormatLogger(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{
+		Level: slog.LevelInfo,
+	}))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		logger.Debugf("iteration %d of %d with %s", i, b.N, "some value")
+	}
+}
+
+func TestFormatLoggerLog(t *testing.T) {
+	var b bytes.Buffer
+	logger := logutil.NewFormatLogger(slog.NewTextHandler(&b, &slog.HandlerOptions{
+		ReplaceAttr: logutil.RemoveKeys(slog.TimeKey),
+	}))
+	// A custom level computed at runtime, between info and warn.
+	logger.Log(slog.LevelInfo+2, "subprocess output", "line", 3)
+	want := `level=INFO+2 msg="subprocess output" line=3` + "\n"
+	if got := b.String(); got != want {
+		t.Errorf("\ngot\n\t%s\nwant\n\t%s", got, want)
+	}
+}
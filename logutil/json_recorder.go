@@ -0,0 +1,55 @@
+package logutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// JSONRecorder is an io.Writer that parses each line written to it as a JSON object
+// and records the result. It is intended to be paired with slog.NewJSONHandler in tests
+// so that assertions can be made about the exact records emitted by a handler
+// without needing a real file.
+//
+// If a written line is not valid JSON, Write will return an error.
+//
+// A JSONRecorder is safe for use by multiple goroutines.
+// The zero value is ready for use.
+type JSONRecorder struct {
+	mu      sync.Mutex
+	buf     bytes.Buffer // holds a partial line until a newline is written
+	records []map[string]any
+}
+
+func (r *JSONRecorder) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.buf.Write(p)
+	// Parse each complete line. Any trailing partial line is kept in the buffer
+	// until the rest of it is written.
+	for {
+		data := r.buf.Bytes()
+		i := bytes.IndexByte(data, '\n')
+		if i == -1 {
+			return len(p), nil
+		}
+		line := data[:i]
+		var record map[string]any
+		if err := json.Unmarshal(line, &record); err != nil {
+			return len(p), fmt.Errorf("logutil: unable to parse line %q as JSON: %w", line, err)
+		}
+		r.records = append(r.records, record)
+		r.buf.Next(i + 1)
+	}
+}
+
+// Records returns all the records that have been parsed so far.
+// The returned slice is a copy and is safe to modify.
+func (r *JSONRecorder) Records() []map[string]any {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	records := make([]map[string]any, len(r.records))
+	copy(records, r.records)
+	return records
+}
@@ -0,0 +1,44 @@
+package logutil_test
+
+import (
+	"log/slog"
+	"testing"
+
+	"github.com/cszatmary/goutils/logutil"
+)
+
+func TestJSONRecorder(t *testing.T) {
+	var r logutil.JSONRecorder
+	logger := slog.New(slog.NewJSONHandler(&r, &slog.HandlerOptions{
+		ReplaceAttr: logutil.RemoveKeys(slog.TimeKey),
+	}))
+	logger.Info("first message", "id", "a1")
+	logger.Warn("second message", "count", 2)
+
+	records := r.Records()
+	if len(records) != 2 {
+		t.Fatalf("got %d records, want 2", len(records))
+	}
+	first := records[0]
+	if first["msg"] != "first message" {
+		t.Errorf("got msg %q, want %q", first["msg"], "first message")
+	}
+	if first["id"] != "a1" {
+		t.Errorf("got id %q, want %q", first["id"], "a1")
+	}
+	second := records[1]
+	if second["level"] != "WARN" {
+		t.Errorf("got level %q, want %q", second["level"], "WARN")
+	}
+	// JSON numbers are unmarshaled as float64.
+	if second["count"] != float64(2) {
+		t.Errorf("got count %v, want 2", second["count"])
+	}
+}
+
+func TestJSONRecorderMalformed(t *testing.T) {
+	var r logutil.JSONRecorder
+	if _, err := r.Write([]byte("not json\n")); err == nil {
+		t.Error("expected an error, got nil")
+	}
+}
@@ -9,13 +9,19 @@ package logutil
 
 import (
 	"bufio"
-	"context"
+	"bytes"
 	"fmt"
 	"io"
+	"log"
 	"log/slog"
+	"regexp"
 	"runtime"
+	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
 
+	"github.com/cszatmary/goutils/color"
 	"github.com/cszatmary/goutils/progress"
 )
 
@@ -52,6 +58,95 @@ func (v *WriterVar) Write(p []byte) (int, error) {
 	return v.w.Write(p)
 }
 
+// CountingWriter is an io.Writer that counts the bytes written through it
+// to an underlying writer. It is useful for byte-level progress reporting,
+// e.g. download progress or spinner byte counters.
+//
+// The count is updated atomically so it is safe to read from other goroutines
+// while writes are in progress. Writes themselves are only as concurrency-safe
+// as the underlying writer.
+//
+// A CountingWriter must not be copied after first use.
+type CountingWriter struct {
+	w io.Writer
+	n atomic.Int64
+}
+
+// NewCountingWriter creates a new CountingWriter that writes to w.
+func NewCountingWriter(w io.Writer) *CountingWriter {
+	return &CountingWriter{w: w}
+}
+
+func (cw *CountingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n.Add(int64(n))
+	return n, err
+}
+
+// Count returns the total number of bytes written so far.
+func (cw *CountingWriter) Count() int64 {
+	return cw.n.Load()
+}
+
+// truncatingWriter is the io.Writer returned by NewTruncatingWriter.
+type truncatingWriter struct {
+	w        io.Writer
+	maxBytes int
+	marker   string
+}
+
+// NewTruncatingWriter returns an io.Writer that truncates each line written
+// through it to at most maxBytes bytes, not counting the trailing newline.
+// Truncated lines end with marker so the truncation is visible.
+//
+// This is intended for capping log record length for sinks that reject overly
+// long lines, e.g. syslog. Since slog handlers serialize one record per line,
+// wrapping the handler's writer truncates per record:
+//
+//	h := slog.NewTextHandler(logutil.NewTruncatingWriter(w, 1024, "..."), nil)
+//
+// Note that truncation operates on bytes, so a multi-byte character may be
+// split at the cut point.
+func NewTruncatingWriter(w io.Writer, maxBytes int, marker string) io.Writer {
+	return &truncatingWriter{w: w, maxBytes: maxBytes, marker: marker}
+}
+
+func (tw *truncatingWriter) Write(p []byte) (int, error) {
+	rem := p
+	for len(rem) > 0 {
+		line := rem
+		var hasNL bool
+		if i := bytes.IndexByte(rem, '\n'); i >= 0 {
+			line = rem[:i]
+			rem = rem[i+1:]
+			hasNL = true
+		} else {
+			rem = nil
+		}
+		if len(line) > tw.maxBytes {
+			cut := tw.maxBytes - len(tw.marker)
+			if cut < 0 {
+				cut = 0
+			}
+			line = line[:cut]
+			if _, err := tw.w.Write(line); err != nil {
+				return 0, err
+			}
+			if _, err := io.WriteString(tw.w, tw.marker); err != nil {
+				return 0, err
+			}
+		} else if _, err := tw.w.Write(line); err != nil {
+			return 0, err
+		}
+		if hasNL {
+			if _, err := tw.w.Write([]byte{'\n'}); err != nil {
+				return 0, err
+			}
+		}
+	}
+	return len(p), nil
+}
+
 // LogWriter returns an io.Writer that can be used to write arbitrary text to the logger.
 // logger is used to log an error if one occurs.
 //
@@ -70,16 +165,8 @@ func LogWriter(logger progress.Logger, level slog.Level) io.WriteCloser {
 	case slog.LevelError:
 		logFunc = logger.Error
 	default:
-		// See if the logger has a Log method that can be passed a level.
-		type withlog interface {
-			Log(context.Context, slog.Level, string, ...any)
-		}
-		wl, ok := logger.(withlog)
-		if !ok {
-			panic(fmt.Errorf("logutil.LogWriter: unsupported level %s(%d)", level.String(), level))
-		}
 		logFunc = func(s string, a ...any) {
-			wl.Log(context.Background(), level, s, a...)
+			logger.Log(level, s, a...)
 		}
 	}
 	go logText(logger, pr, logFunc)
@@ -87,6 +174,17 @@ func LogWriter(logger progress.Logger, level slog.Level) io.WriteCloser {
 	return pw
 }
 
+// StdLogger returns a standard library *log.Logger whose output is routed
+// line by line into logger at the given level, using the LogWriter machinery.
+// This makes it possible to pass a structured logger to dependencies that
+// only accept a *log.Logger.
+//
+// Since the returned logger writes through a pipe on a separate goroutine,
+// logs may not appear immediately after a print call returns.
+func StdLogger(logger progress.Logger, level slog.Level) *log.Logger {
+	return log.New(LogWriter(logger, level), "", 0)
+}
+
 func logText(logger progress.Logger, pr *io.PipeReader, logFunc func(string, ...any)) {
 	s := bufio.NewScanner(pr)
 	for s.Scan() {
@@ -98,6 +196,57 @@ func logText(logger progress.Logger, pr *io.PipeReader, logFunc func(string, ...
 	pr.Close()
 }
 
+// LevelColorFunc returns the colour function from c that should be used to
+// colourize logs at the given level: white for debug, cyan for info, yellow
+// for warn, and red for error. Levels in between round down, e.g. anything
+// between info and warn uses the info colour.
+//
+// This is the single source of truth for the level to colour mapping so
+// handlers that colourize by level stay consistent.
+func LevelColorFunc(c *color.Colorer, level slog.Level) func(string) string {
+	switch {
+	case level < slog.LevelInfo:
+		return c.White
+	case level < slog.LevelWarn:
+		return c.Cyan
+	case level < slog.LevelError:
+		return c.Yellow
+	default:
+		return c.Red
+	}
+}
+
+// ParseLevel parses a slog-style level string such as "info" or "DEBUG+2".
+// The level name is matched case-insensitively and may be followed by a
+// +N or -N offset, mirroring the format produced by [slog.Level.String].
+// An error is returned if s is not a valid level.
+func ParseLevel(s string) (slog.Level, error) {
+	name := s
+	var offset int
+	if i := strings.IndexAny(s, "+-"); i >= 0 {
+		name = s[:i]
+		n, err := strconv.Atoi(s[i:])
+		if err != nil {
+			return 0, fmt.Errorf("logutil: invalid level %q", s)
+		}
+		offset = n
+	}
+	var l slog.Level
+	switch strings.ToLower(name) {
+	case "debug":
+		l = slog.LevelDebug
+	case "info":
+		l = slog.LevelInfo
+	case "warn":
+		l = slog.LevelWarn
+	case "error":
+		l = slog.LevelError
+	default:
+		return 0, fmt.Errorf("logutil: invalid level %q", s)
+	}
+	return l + slog.Level(offset), nil
+}
+
 // CallerPC returns the program counter at the given stack depth.
 func CallerPC(depth int) uintptr {
 	var pcs [1]uintptr
@@ -130,3 +279,42 @@ func RemoveKeys(keys ...string) func([]string, slog.Attr) slog.Attr {
 		return a
 	}
 }
+
+// RedactPatterns returns a function suitable for HandlerOptions.ReplaceAttr
+// that replaces any substring of a string attr value matching one of the given
+// patterns with replacement. Only string-kind values are touched so structured
+// data is not corrupted.
+//
+// This complements key-based redaction for cases where sensitive data appears
+// in free-form values, e.g. a bearer token embedded in a URL.
+func RedactPatterns(patterns []*regexp.Regexp, replacement string) func([]string, slog.Attr) slog.Attr {
+	return func(_ []string, a slog.Attr) slog.Attr {
+		if a.Value.Kind() != slog.KindString {
+			return a
+		}
+		s := a.Value.String()
+		for _, p := range patterns {
+			s = p.ReplaceAllString(s, replacement)
+		}
+		a.Value = slog.StringValue(s)
+		return a
+	}
+}
+
+// ChainReplaceAttr returns a function suitable for HandlerOptions.ReplaceAttr
+// that applies each of the given functions in sequence. If a function returns
+// an empty Attr, the chain short-circuits and the Attr is dropped.
+//
+// This makes it easy to combine multiple transforms, e.g. RemoveKeys with
+// a rename or redaction function.
+func ChainReplaceAttr(fns ...func([]string, slog.Attr) slog.Attr) func([]string, slog.Attr) slog.Attr {
+	return func(groups []string, a slog.Attr) slog.Attr {
+		for _, fn := range fns {
+			a = fn(groups, a)
+			if a.Equal(slog.Attr{}) {
+				return a
+			}
+		}
+		return a
+	}
+}
@@ -2,7 +2,9 @@ package logutil
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
+	"slices"
 
 	"github.com/cszatmary/goutils/errors"
 )
@@ -17,7 +19,17 @@ import (
 // in a file for debugging.
 type MultiHandler struct {
 	handlers []slog.Handler
-	opts     MultiHandlerOptions
+	// names identifies each handler in errors returned by Handle.
+	// It is only set by NewNamedMultiHandler and is parallel to handlers.
+	names []string
+	opts  MultiHandlerOptions
+	// Attrs and groups are stored once on the MultiHandler and applied lazily
+	// at Handle time instead of eagerly calling WithAttrs/WithGroup on each
+	// handler, which would store copies of the attrs N times.
+	// groups holds the open group names and attrs holds the attrs added at each
+	// group depth, so len(attrs) == len(groups)+1.
+	groups []string
+	attrs  [][]slog.Attr
 }
 
 type MultiHandlerOptions struct {
@@ -38,6 +50,27 @@ func NewMultiHandler(handlers []slog.Handler, opts *MultiHandlerOptions) *MultiH
 	return &MultiHandler{handlers: handlers, opts: *opts}
 }
 
+// NewNamedMultiHandler is like NewMultiHandler but gives each handler a name
+// that is used to identify it in any errors returned by Handle,
+// e.g. "file handler: write error". This makes it easy to tell which handler
+// in a logging pipeline failed. Handlers are processed in sorted name order
+// so behaviour is deterministic.
+func NewNamedMultiHandler(handlers map[string]slog.Handler, opts *MultiHandlerOptions) *MultiHandler {
+	if opts == nil {
+		opts = &MultiHandlerOptions{}
+	}
+	names := make([]string, 0, len(handlers))
+	for name := range handlers {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	hs := make([]slog.Handler, len(names))
+	for i, name := range names {
+		hs[i] = handlers[name]
+	}
+	return &MultiHandler{handlers: hs, names: names, opts: *opts}
+}
+
 func (h *MultiHandler) Enabled(_ context.Context, level slog.Level) bool {
 	// If no level is set, then the handler is always enabled so that each
 	// individual handler can process the record.
@@ -47,30 +80,93 @@ func (h *MultiHandler) Enabled(_ context.Context, level slog.Level) bool {
 	return level >= h.opts.Level.Level()
 }
 
+// MinLevel returns the lowest standard level that at least one of the handlers
+// would handle, determined by probing each handler's Enabled method. This can be
+// used by callers to decide whether it is worth building expensive attributes.
+//
+// If no handler is enabled at any standard level, a level above slog.LevelError
+// is returned.
+func (h *MultiHandler) MinLevel() slog.Level {
+	ctx := context.Background()
+	for _, l := range []slog.Level{slog.LevelDebug, slog.LevelInfo, slog.LevelWarn, slog.LevelError} {
+		if !h.Enabled(ctx, l) {
+			continue
+		}
+		for _, hh := range h.handlers {
+			if hh.Enabled(ctx, l) {
+				return l
+			}
+		}
+	}
+	return slog.LevelError + 1
+}
+
 func (h *MultiHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
-	handlers := make([]slog.Handler, len(h.handlers))
-	for i, h := range h.handlers {
-		handlers[i] = h.WithAttrs(attrs)
+	if len(attrs) == 0 {
+		return h
 	}
-	return &MultiHandler{handlers: handlers, opts: h.opts}
+	h2 := h.clone()
+	i := len(h2.attrs) - 1
+	h2.attrs[i] = append(h2.attrs[i], attrs...)
+	return h2
 }
 
 func (h *MultiHandler) WithGroup(name string) slog.Handler {
-	handlers := make([]slog.Handler, len(h.handlers))
-	for i, h := range h.handlers {
-		handlers[i] = h.WithGroup(name)
+	if name == "" {
+		return h
 	}
-	return &MultiHandler{handlers: handlers, opts: h.opts}
+	h2 := h.clone()
+	h2.groups = append(h2.groups, name)
+	h2.attrs = append(h2.attrs, nil)
+	return h2
+}
+
+func (h *MultiHandler) clone() *MultiHandler {
+	h2 := &MultiHandler{
+		handlers: h.handlers,
+		names:    h.names,
+		opts:     h.opts,
+		groups:   slices.Clip(h.groups),
+		attrs:    make([][]slog.Attr, len(h.attrs)),
+	}
+	for i, attrs := range h.attrs {
+		h2.attrs[i] = slices.Clip(attrs)
+	}
+	if len(h2.attrs) == 0 {
+		h2.attrs = [][]slog.Attr{nil}
+	}
+	return h2
 }
 
 // Handle calls Handle on each handler.
 func (h *MultiHandler) Handle(ctx context.Context, r slog.Record) error {
+	if len(h.groups) > 0 || (len(h.attrs) > 0 && len(h.attrs[0]) > 0) {
+		// Build a new record with the stored attrs and groups prepended so each
+		// handler sees the same record it would have if WithAttrs/WithGroup had
+		// been applied to it directly. Open groups are represented as group-valued attrs.
+		inner := slices.Clip(h.attrs[len(h.attrs)-1])
+		r.Attrs(func(a slog.Attr) bool {
+			inner = append(inner, a)
+			return true
+		})
+		for i := len(h.groups) - 1; i >= 0; i-- {
+			grouped := slog.Attr{Key: h.groups[i], Value: slog.GroupValue(inner...)}
+			inner = append(slices.Clip(h.attrs[i]), grouped)
+		}
+		r2 := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+		r2.AddAttrs(inner...)
+		r = r2
+	}
+
 	var errs errors.List
-	for _, h := range h.handlers {
-		if !h.Enabled(ctx, r.Level) {
+	for i, hh := range h.handlers {
+		if !hh.Enabled(ctx, r.Level) {
 			continue
 		}
-		if err := h.Handle(ctx, r); err != nil {
+		if err := hh.Handle(ctx, r); err != nil {
+			if len(h.names) > 0 {
+				err = fmt.Errorf("%s handler: %w", h.names[i], err)
+			}
 			errs = append(errs, err)
 		}
 	}
@@ -3,6 +3,8 @@ package logutil_test
 import (
 	"bytes"
 	"context"
+	"errors"
+	"io"
 	"log/slog"
 	"testing"
 	"time"
@@ -50,6 +52,52 @@ func TestMultiHandlerEnabled(t *testing.T) {
 	}
 }
 
+func TestMultiHandlerMinLevel(t *testing.T) {
+	tests := []struct {
+		name   string
+		opts   *logutil.MultiHandlerOptions
+		levels []slog.Level
+		want   slog.Level
+	}{
+		{
+			"different levels",
+			nil,
+			[]slog.Level{slog.LevelWarn, slog.LevelInfo},
+			slog.LevelInfo,
+		},
+		{
+			"same levels",
+			nil,
+			[]slog.Level{slog.LevelError, slog.LevelError},
+			slog.LevelError,
+		},
+		{
+			"multi handler level takes precedence",
+			&logutil.MultiHandlerOptions{Level: slog.LevelWarn},
+			[]slog.Level{slog.LevelDebug},
+			slog.LevelWarn,
+		},
+		{
+			"no handlers",
+			nil,
+			nil,
+			slog.LevelError + 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var handlers []slog.Handler
+			for _, l := range tt.levels {
+				handlers = append(handlers, slog.NewTextHandler(io.Discard, &slog.HandlerOptions{Level: l}))
+			}
+			h := logutil.NewMultiHandler(handlers, tt.opts)
+			if got := h.MinLevel(); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestMultiHandler(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -81,6 +129,16 @@ func TestMultiHandler(t *testing.T) {
 			wantText: `time=2000-01-02T03:04:05.000Z level=INFO msg="a message" group.foo=bar` + "\n",
 			wantJSON: `{"time":"2000-01-02T03:04:05Z","level":"INFO","msg":"a message","group":{"foo":"bar"}}` + "\n",
 		},
+		{
+			name: "interleaved WithAttrs and WithGroup",
+			with: func(h slog.Handler) slog.Handler {
+				return h.WithAttrs([]slog.Attr{slog.String("baz", "qux")}).
+					WithGroup("group").
+					WithAttrs([]slog.Attr{slog.String("a", "b")})
+			},
+			wantText: `time=2000-01-02T03:04:05.000Z level=INFO msg="a message" baz=qux group.a=b group.foo=bar` + "\n",
+			wantJSON: `{"time":"2000-01-02T03:04:05Z","level":"INFO","msg":"a message","baz":"qux","group":{"a":"b","foo":"bar"}}` + "\n",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -106,3 +164,56 @@ func TestMultiHandler(t *testing.T) {
 		})
 	}
 }
+
+func BenchmarkMultiHandlerWithAttrs(b *testing.B) {
+	h := slog.Handler(logutil.NewMultiHandler([]slog.Handler{
+		slog.NewTextHandler(io.Discard, nil),
+		slog.NewJSONHandler(io.Discard, nil),
+	}, nil))
+	attrs := []slog.Attr{
+		slog.String("a", "b"),
+		slog.Int("c", 1),
+		slog.Bool("d", true),
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		h.WithAttrs(attrs)
+	}
+}
+
+// failingHandler is a slog.Handler whose Handle always returns err.
+type failingHandler struct {
+	slog.Handler
+	err error
+}
+
+func (h failingHandler) Handle(context.Context, slog.Record) error {
+	return h.err
+}
+
+func TestNamedMultiHandler(t *testing.T) {
+	var buf bytes.Buffer
+	h := logutil.NewNamedMultiHandler(map[string]slog.Handler{
+		"file": failingHandler{
+			Handler: slog.NewTextHandler(io.Discard, nil),
+			err:     errors.New("write error"),
+		},
+		"stdout": slog.NewTextHandler(&buf, &slog.HandlerOptions{
+			ReplaceAttr: logutil.RemoveKeys(slog.TimeKey),
+		}),
+	}, nil)
+
+	r := slog.NewRecord(testTime, slog.LevelInfo, "message", 0)
+	err := h.Handle(context.Background(), r)
+	if err == nil {
+		t.Fatal("expected an error, got nil")
+	}
+	want := "file handler: write error"
+	if err.Error() != want {
+		t.Errorf("got error %q, want %q", err, want)
+	}
+	// The other handler should still have received the record.
+	if got, want := buf.String(), `level=INFO msg=message`+"\n"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -4,16 +4,20 @@ import (
 	"bytes"
 	"context"
 	"encoding"
+	"encoding/json"
 	"fmt"
 	"io"
 	"log/slog"
 	"reflect"
 	"slices"
 	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/cszatmary/goutils/color"
+	"github.com/cszatmary/goutils/text"
 )
 
 // PrettyHandler is a Handler that writes Records to an io.Writer in a pretty format that looks like so:
@@ -27,6 +31,32 @@ type PrettyHandler struct {
 	attrsList   []attrsNode
 	groupPrefix string
 	groups      []string
+	// levelWidth is the width the rendered level is padded to.
+	levelWidth int
+	// colWidths tracks column widths if the Columns option is used.
+	// It is shared by all handlers derived via WithAttrs/WithGroup.
+	colWidths *columnWidths
+	// stopped reports that writing has been permanently stopped due to a write
+	// error. It is shared by all handlers derived via WithAttrs/WithGroup.
+	stopped *atomic.Bool
+}
+
+// columnWidths tracks the widest value seen so far for each column.
+// It is safe for use by multiple goroutines.
+type columnWidths struct {
+	mu     sync.Mutex
+	widths map[string]int
+}
+
+// observe records that a value of width w was seen for the column key and
+// returns the width the value should be padded to.
+func (cw *columnWidths) observe(key string, w int) int {
+	cw.mu.Lock()
+	defer cw.mu.Unlock()
+	if w > cw.widths[key] {
+		cw.widths[key] = w
+	}
+	return cw.widths[key]
 }
 
 // PrettyHandlerOptions are options for a PrettyHandler.
@@ -48,12 +78,108 @@ type PrettyHandlerOptions struct {
 	// By default, quoting will only be applied if required.
 	ForceQuote bool
 
+	// LevelText is called to get the text used to render a record's level,
+	// e.g. single-letter levels for dense logs. The rendered level is padded
+	// to the longest text returned for the standard levels so records line up.
+	// If omitted, the level's String method is used, padded to 5 characters.
+	LevelText func(level slog.Level) string
+
+	// DurationUnit causes duration values to be rendered as a number in the
+	// given unit with its suffix, e.g. with time.Millisecond a duration of
+	// 1.5s renders as "1500ms". This keeps durations in a consistent unit
+	// which is easier to scan and parse. The unit must be one of
+	// time.Nanosecond, time.Microsecond, time.Millisecond, time.Second,
+	// time.Minute, or time.Hour.
+	// If omitted, durations are rendered using Duration.String.
+	DurationUnit time.Duration
+
+	// SourceFormat is called to format source code position information if
+	// AddSource is set. It can be used to customize how the source is displayed,
+	// e.g. trimming the file to its base name.
+	// If omitted, the source is formatted as "file:line" using the full path.
+	SourceFormat func(src *slog.Source) string
+
+	// FullSource renders the source as "function file:line", matching the
+	// information slog.TextHandler provides, instead of the abbreviated
+	// "file:line" form. This is useful for deep debugging where the function
+	// name matters. It is ignored if SourceFormat is set.
+	FullSource bool
+
 	// Disables using colours in logs.
 	DisableColor bool
+
+	// Columns is a set of attr keys that should be aligned into columns across records.
+	// The listed attrs are written first in the given order, each padded to the widest
+	// value seen so far for that column so keys line up vertically. Attrs not listed
+	// are appended at the end as usual.
+	//
+	// Since a handler only sees one record at a time, the column widths grow as
+	// records are handled; earlier records are not rewritten.
+	Columns []string
+
+	// KeyValueSep is the separator written between an attr's key and value.
+	// If omitted, it defaults to "=".
+	KeyValueSep string
+
+	// PairSep is the separator written between attrs (and the built-in fields),
+	// e.g. "\t" for tab-separated output that is easier to cut/grep.
+	// If omitted, it defaults to a single space.
+	PairSep string
+
+	// NoQuote reports whether an attr's value should be written raw, bypassing
+	// the usual quoting rules. This is useful for pre-formatted values that are
+	// intentionally structured, e.g. a json.RawMessage that should appear
+	// inline rather than as an escaped string.
+	// The attr passed to NoQuote has already been resolved and rewritten by
+	// ReplaceAttr. Keys are never affected, only values.
+	NoQuote func(a slog.Attr) bool
+
+	// OnWriteError is called if writing a record to the writer fails.
+	// If it returns true, the handler stops attempting any further writes and
+	// Handle becomes a no-op. This is useful to handle a broken pipe, e.g.
+	// when stdout is piped to a program like head that closes the pipe early,
+	// without spamming errors for every subsequent log.
+	// If omitted, write errors are returned from Handle as usual.
+	OnWriteError func(err error) bool
+}
+
+// ValidatePrettyHandlerOptions checks opts for conflicting or invalid option
+// combinations and returns an error describing the first problem found.
+// NewPrettyHandler normalizes these cases silently, so validation is optional;
+// it is intended for callers building options from user configuration who want
+// to surface misconfiguration explicitly instead of getting odd output.
+func ValidatePrettyHandlerOptions(opts *PrettyHandlerOptions) error {
+	if opts == nil {
+		return nil
+	}
+	if opts.DurationUnit != 0 && formatDurationUnit(0, opts.DurationUnit) == "" {
+		return fmt.Errorf("logutil: unsupported duration unit %s", opts.DurationUnit)
+	}
+	kvSep, pairSep := opts.KeyValueSep, opts.PairSep
+	if kvSep == "" {
+		kvSep = "="
+	}
+	if pairSep == "" {
+		pairSep = " "
+	}
+	if kvSep == pairSep {
+		return fmt.Errorf("logutil: KeyValueSep and PairSep are both %q, output would be ambiguous", kvSep)
+	}
+	if opts.SourceFormat != nil && opts.FullSource {
+		return fmt.Errorf("logutil: FullSource is ignored when SourceFormat is set")
+	}
+	for _, c := range opts.Columns {
+		if c == "" {
+			return fmt.Errorf("logutil: Columns contains an empty key")
+		}
+	}
+	return nil
 }
 
 // NewPrettyHandler creates a new PrettyHandler that writes to the given writer,
 // using the given options. If opts is nil, the default options are used.
+// Conflicting options are normalized rather than rejected, e.g. an unsupported
+// DurationUnit is ignored; use ValidatePrettyHandlerOptions to get explicit errors.
 func NewPrettyHandler(w io.Writer, opts *PrettyHandlerOptions) *PrettyHandler {
 	var o PrettyHandlerOptions
 	if opts != nil {
@@ -62,9 +188,32 @@ func NewPrettyHandler(w io.Writer, opts *PrettyHandlerOptions) *PrettyHandler {
 	if o.Level == nil {
 		o.Level = slog.LevelInfo
 	}
+	// Ignore an unsupported duration unit instead of producing empty values.
+	if o.DurationUnit != 0 && formatDurationUnit(0, o.DurationUnit) == "" {
+		o.DurationUnit = 0
+	}
+	if o.KeyValueSep == "" {
+		o.KeyValueSep = "="
+	}
+	if o.PairSep == "" {
+		o.PairSep = " "
+	}
 	var c color.Colorer
 	c.SetEnabled(!o.DisableColor)
-	return &PrettyHandler{opts: o, w: w, c: c}
+	h := &PrettyHandler{opts: o, w: w, c: c, levelWidth: 5, stopped: new(atomic.Bool)}
+	if o.LevelText != nil {
+		// Pad levels to the longest text for the standard levels so they line up.
+		h.levelWidth = 0
+		for _, l := range []slog.Level{slog.LevelDebug, slog.LevelInfo, slog.LevelWarn, slog.LevelError} {
+			if n := len(o.LevelText(l)); n > h.levelWidth {
+				h.levelWidth = n
+			}
+		}
+	}
+	if len(o.Columns) > 0 {
+		h.colWidths = &columnWidths{widths: make(map[string]int)}
+	}
+	return h
 }
 
 func (h *PrettyHandler) clone() *PrettyHandler {
@@ -75,6 +224,9 @@ func (h *PrettyHandler) clone() *PrettyHandler {
 		attrsList:   slices.Clip(h.attrsList),
 		groupPrefix: h.groupPrefix,
 		groups:      slices.Clip(h.groups),
+		levelWidth:  h.levelWidth,
+		colWidths:   h.colWidths,
+		stopped:     h.stopped,
 	}
 }
 
@@ -108,20 +260,15 @@ func (h *PrettyHandler) WithGroup(name string) slog.Handler {
 }
 
 func (h *PrettyHandler) Handle(_ context.Context, r slog.Record) error {
+	// If a previous write failed and OnWriteError requested a stop,
+	// drop the record instead of attempting another doomed write.
+	if h.stopped.Load() {
+		return nil
+	}
 	b := newBuffer()
 	defer freeBuffer(b)
 
-	var colorFunc func(string) string
-	switch r.Level {
-	case slog.LevelDebug:
-		colorFunc = h.c.White
-	case slog.LevelInfo:
-		colorFunc = h.c.Cyan
-	case slog.LevelWarn:
-		colorFunc = h.c.Yellow
-	case slog.LevelError:
-		colorFunc = h.c.Red
-	}
+	colorFunc := LevelColorFunc(&h.c, r.Level)
 
 	// Treat all built-in fields as Attrs, this simplifies the branching needed here to handle ReplaceAttr.
 	// appendAttr will figure out how to handle everything correctly.
@@ -137,32 +284,95 @@ func (h *PrettyHandler) Handle(_ context.Context, r slog.Record) error {
 	h.appendAttr(b, slog.String(slog.MessageKey, r.Message), state{colorFunc: colorFunc})
 
 	// attrs
-	if len(h.attrsList) > 0 {
-		for _, n := range h.attrsList {
-			s := state{n.groupPrefix, n.groups, colorFunc}
-			for _, a := range n.attrs {
-				h.appendAttr(b, a, s)
+	if len(h.opts.Columns) > 0 {
+		h.appendColumns(b, r, colorFunc)
+	} else {
+		if len(h.attrsList) > 0 {
+			for _, n := range h.attrsList {
+				s := state{n.groupPrefix, n.groups, colorFunc}
+				for _, a := range n.attrs {
+					h.appendAttr(b, a, s)
+				}
 			}
 		}
+		r.Attrs(func(a slog.Attr) bool {
+			h.appendAttr(b, a, state{h.groupPrefix, h.groups, colorFunc})
+			return true
+		})
 	}
-	r.Attrs(func(a slog.Attr) bool {
-		h.appendAttr(b, a, state{h.groupPrefix, h.groups, colorFunc})
-		return true
-	})
 	data := b.Bytes()
 	if len(data) > 0 {
-		// If there was any data written there must be a trailing space
-		// since appendAttr always adds a space at the end.
-		// Replace the final space with a newline.
-		data[len(data)-1] = '\n'
+		// If there was any data written there must be a trailing pair separator
+		// since appendAttr always adds one at the end.
+		// Replace the final separator with a newline.
+		data = data[:len(data)-len(h.opts.PairSep)]
+		data = append(data, '\n')
 	}
 
 	h.mu.Lock()
 	defer h.mu.Unlock()
 	_, err := h.w.Write(data)
+	if err != nil && h.opts.OnWriteError != nil && h.opts.OnWriteError(err) {
+		h.stopped.Store(true)
+	}
 	return err
 }
 
+// appendColumns writes the record's attrs aligned into the configured columns.
+// Attrs whose key is listed in the Columns option are written first in the
+// configured order, padded to the widest value seen so far for that column.
+// Remaining attrs are appended at the end.
+func (h *PrettyHandler) appendColumns(b *bytes.Buffer, r slog.Record, colorFunc func(string) string) {
+	type cell struct {
+		key string
+		str string
+	}
+	var cells []cell
+	render := func(a slog.Attr, s state) {
+		tb := newBuffer()
+		h.appendAttr(tb, a, s)
+		// appendAttr adds a trailing separator after each attr, remove it since
+		// the spacing is handled by the column padding.
+		str := strings.TrimSuffix(tb.String(), h.opts.PairSep)
+		freeBuffer(tb)
+		if str != "" {
+			cells = append(cells, cell{a.Key, str})
+		}
+	}
+	for _, n := range h.attrsList {
+		s := state{n.groupPrefix, n.groups, colorFunc}
+		for _, a := range n.attrs {
+			render(a, s)
+		}
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		render(a, state{h.groupPrefix, h.groups, colorFunc})
+		return true
+	})
+
+	for _, key := range h.opts.Columns {
+		str := ""
+		for i, c := range cells {
+			if c.key == key {
+				str = c.str
+				cells = slices.Delete(cells, i, i+1)
+				break
+			}
+		}
+		// Measure the display width so colour codes don't throw off the padding.
+		w := text.DisplayWidth(str)
+		b.WriteString(str)
+		for i := w; i < h.colWidths.observe(key, w); i++ {
+			b.WriteByte(' ')
+		}
+		b.WriteString(h.opts.PairSep)
+	}
+	for _, c := range cells {
+		b.WriteString(c.str)
+		b.WriteString(h.opts.PairSep)
+	}
+}
+
 func (h *PrettyHandler) appendAttr(b *bytes.Buffer, a slog.Attr, s state) {
 	if rep := h.opts.ReplaceAttr; rep != nil && a.Value.Kind() != slog.KindGroup {
 		// Resolve before calling ReplaceAttr so the caller doesn't have to.
@@ -190,10 +400,22 @@ func (h *PrettyHandler) appendAttr(b *bytes.Buffer, a slog.Attr, s state) {
 		}
 		return
 	}
+	// Special case, format durations in a fixed unit if configured.
+	if h.opts.DurationUnit != 0 && a.Value.Kind() == slog.KindDuration {
+		if s := formatDurationUnit(a.Value.Duration(), h.opts.DurationUnit); s != "" {
+			a.Value = slog.StringValue(s)
+		}
+	}
 	// Special case, stringify source nicely.
 	if v := a.Value; v.Kind() == slog.KindAny {
 		if src, ok := v.Any().(*slog.Source); ok {
-			a.Value = slog.StringValue(fmt.Sprintf("%s:%d", src.File, src.Line))
+			if h.opts.SourceFormat != nil {
+				a.Value = slog.StringValue(h.opts.SourceFormat(src))
+			} else if h.opts.FullSource {
+				a.Value = slog.StringValue(fmt.Sprintf("%s %s:%d", src.Function, src.File, src.Line))
+			} else {
+				a.Value = slog.StringValue(fmt.Sprintf("%s:%d", src.File, src.Line))
+			}
 		}
 	}
 
@@ -202,10 +424,14 @@ func (h *PrettyHandler) appendAttr(b *bytes.Buffer, a slog.Attr, s state) {
 		b.WriteString(stringify(a.Value))
 	} else if a.Key == slog.LevelKey {
 		if l, ok := a.Value.Any().(slog.Level); ok {
+			text := l.String()
+			if h.opts.LevelText != nil {
+				text = h.opts.LevelText(l)
+			}
 			// Pad level so that it is the same length for every line, i.e.
 			// "INFO "
 			// "DEBUG"
-			str := fmt.Sprintf("%-5s", l.String())
+			str := fmt.Sprintf("%-*s", h.levelWidth, text)
 			if s.colorFunc != nil {
 				str = s.colorFunc(str)
 			}
@@ -222,10 +448,14 @@ func (h *PrettyHandler) appendAttr(b *bytes.Buffer, a slog.Attr, s state) {
 	} else {
 		// Handle remaining attrs.
 		h.appendString(b, s.groupPrefix+a.Key, s.colorFunc)
-		b.WriteByte('=')
-		h.appendString(b, stringify(a.Value), nil)
+		b.WriteString(h.opts.KeyValueSep)
+		if h.opts.NoQuote != nil && h.opts.NoQuote(a) {
+			b.WriteString(stringify(a.Value))
+		} else {
+			h.appendString(b, stringify(a.Value), nil)
+		}
 	}
-	b.WriteByte(' ')
+	b.WriteString(h.opts.PairSep)
 }
 
 type state struct {
@@ -248,6 +478,14 @@ func (h *PrettyHandler) needsQuoting(s string) bool {
 	if h.opts.ForceQuote || s == "" {
 		return true
 	}
+	// Quote values containing a configured separator so output stays unambiguous,
+	// e.g. a value containing ":" when KeyValueSep is ":".
+	if h.opts.KeyValueSep != "=" && strings.Contains(s, h.opts.KeyValueSep) {
+		return true
+	}
+	if h.opts.PairSep != " " && strings.Contains(s, h.opts.PairSep) {
+		return true
+	}
 	for _, c := range s {
 		// Needs to be quoted if it's not alphanumeric and not one of the special chars below.
 		if !((c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') ||
@@ -258,6 +496,29 @@ func (h *PrettyHandler) needsQuoting(s string) bool {
 	return false
 }
 
+// formatDurationUnit renders d as a number in the given unit with its suffix.
+// It returns an empty string if unit is not a supported unit.
+func formatDurationUnit(d, unit time.Duration) string {
+	var suffix string
+	switch unit {
+	case time.Nanosecond:
+		suffix = "ns"
+	case time.Microsecond:
+		suffix = "µs"
+	case time.Millisecond:
+		suffix = "ms"
+	case time.Second:
+		suffix = "s"
+	case time.Minute:
+		suffix = "m"
+	case time.Hour:
+		suffix = "h"
+	default:
+		return ""
+	}
+	return strconv.FormatFloat(float64(d)/float64(unit), 'f', -1, 64) + suffix
+}
+
 func stringify(v slog.Value) string {
 	switch v.Kind() {
 	case slog.KindBool:
@@ -283,6 +544,15 @@ func stringify(v slog.Value) string {
 			}
 			return string(data)
 		}
+		// Prefer a type's own String method so common types render cleanly
+		// instead of going through %+v of the slog.Value.
+		if sv, ok := vv.(fmt.Stringer); ok {
+			// Guard against a typed nil pointer, since calling String on it
+			// would likely panic.
+			if rv := reflect.ValueOf(vv); rv.Kind() != reflect.Pointer || !rv.IsNil() {
+				return sv.String()
+			}
+		}
 		// Handle byte slices specially and try and print it nicely.
 		if bs, ok := vv.([]byte); ok {
 			return string(bs)
@@ -296,6 +566,19 @@ func stringify(v slog.Value) string {
 			// These can't be printed properly and fmt.Sprint will generate an
 			// ugly pointer representation, ex: %!s(func()=0x10bf380)
 			switch t.Kind() {
+			case reflect.Map, reflect.Slice, reflect.Array, reflect.Struct:
+				// Render structured values as compact JSON so they are readable
+				// and machine-parseable instead of Go's map[...] syntax.
+				// Types that define their own representation are excluded and
+				// handled by the fmt fallthrough below.
+				_, isErr := vv.(error)
+				_, isStringer := vv.(fmt.Stringer)
+				if !isErr && !isStringer {
+					if data, err := json.Marshal(vv); err == nil {
+						return string(data)
+					}
+					// Fall through to fmt if the value can't be marshalled.
+				}
 			case reflect.Chan:
 				name := t.Elem().Name()
 				if name == "" {
@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"log/slog"
 	"path/filepath"
@@ -11,6 +13,7 @@ import (
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/cszatmary/goutils/logutil"
 )
@@ -46,6 +49,26 @@ func TestPrettyHandler(t *testing.T) {
 			attrs: append(slices.Clip(attrs), slog.Any("", "v")),
 			want:  `2000-01-02T03:04:05Z INFO  message                                      a=one b=2 ""=v`,
 		},
+		{
+			name:  "map value",
+			attrs: []slog.Attr{slog.Any("labels", map[string]int{"b": 2, "a": 1})},
+			want:  `2000-01-02T03:04:05Z INFO  message                                      labels="{\"a\":1,\"b\":2}"`,
+		},
+		{
+			name:  "slice value",
+			attrs: []slog.Attr{slog.Any("ids", []int{1, 2, 3})},
+			want:  `2000-01-02T03:04:05Z INFO  message                                      ids="[1,2,3]"`,
+		},
+		{
+			name:  "struct value",
+			attrs: []slog.Attr{slog.Any("point", struct{ X, Y int }{1, 2})},
+			want:  `2000-01-02T03:04:05Z INFO  message                                      point="{\"X\":1,\"Y\":2}"`,
+		},
+		{
+			name:  "stringer value",
+			attrs: []slog.Attr{slog.Any("state", stringerState(1))},
+			want:  `2000-01-02T03:04:05Z INFO  message                                      state=running`,
+		},
 		{
 			name:    "cap keys",
 			replace: upperCaseKey,
@@ -393,3 +416,271 @@ func TestReplaceAttrGroups(t *testing.T) {
 		t.Errorf("\ngot  %v\nwant %v", got, want)
 	}
 }
+
+func TestPrettyHandlerColumns(t *testing.T) {
+	var buf bytes.Buffer
+	h := logutil.NewPrettyHandler(&buf, &logutil.PrettyHandlerOptions{
+		ReplaceAttr:  logutil.RemoveKeys(slog.TimeKey, slog.LevelKey),
+		DisableColor: true,
+		Columns:      []string{"service", "status"},
+	})
+	logger := slog.New(h)
+	logger.Info("first", "service", "gateway", "status", "ok", "extra", "one")
+	logger.Info("second", "service", "api", "status", "ok")
+
+	// The second record's service attr must be padded to the width of the
+	// first record's so the columns line up. Unlisted attrs go at the end.
+	want := "first                                        service=gateway status=ok extra=one\n" +
+		"second                                       service=api     status=ok\n"
+	if got := buf.String(); got != want {
+		t.Errorf("\ngot\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestPrettyHandlerSourceFormat(t *testing.T) {
+	r := slog.NewRecord(testTime, slog.LevelInfo, "message", logutil.CallerPC(1))
+	src := logutil.CallerSource(r.PC)
+	line := strconv.Itoa(src.Line)
+
+	var buf bytes.Buffer
+	h := logutil.NewPrettyHandler(&buf, &logutil.PrettyHandlerOptions{
+		AddSource:    true,
+		ReplaceAttr:  logutil.RemoveKeys(slog.TimeKey, slog.LevelKey, slog.MessageKey),
+		DisableColor: true,
+		SourceFormat: func(src *slog.Source) string {
+			return fmt.Sprintf("%s:%d", filepath.Base(src.File), src.Line)
+		},
+	})
+	if err := h.Handle(context.Background(), r); err != nil {
+		t.Fatal(err)
+	}
+	want := "pretty_handler_test.go:" + line + "\n"
+	if got := buf.String(); got != want {
+		t.Errorf("\ngot  %s\nwant %s", got, want)
+	}
+}
+
+func TestPrettyHandlerFullSource(t *testing.T) {
+	r := slog.NewRecord(testTime, slog.LevelInfo, "message", logutil.CallerPC(1))
+	src := logutil.CallerSource(r.PC)
+
+	var buf bytes.Buffer
+	h := logutil.NewPrettyHandler(&buf, &logutil.PrettyHandlerOptions{
+		AddSource:    true,
+		ReplaceAttr:  logutil.RemoveKeys(slog.TimeKey, slog.LevelKey, slog.MessageKey),
+		DisableColor: true,
+		FullSource:   true,
+	})
+	if err := h.Handle(context.Background(), r); err != nil {
+		t.Fatal(err)
+	}
+	want := fmt.Sprintf("%s %s:%d\n", src.Function, src.File, src.Line)
+	if got := buf.String(); got != want {
+		t.Errorf("\ngot  %s\nwant %s", got, want)
+	}
+	if !strings.Contains(buf.String(), "TestPrettyHandlerFullSource") {
+		t.Errorf("want source to contain the function name, got %s", buf.String())
+	}
+}
+
+func TestPrettyHandlerDurationUnit(t *testing.T) {
+	tests := []struct {
+		name string
+		unit time.Duration
+		d    time.Duration
+		want string
+	}{
+		{"milliseconds", time.Millisecond, 1500 * time.Millisecond, "took=1500ms"},
+		{"seconds", time.Second, 90 * time.Second, "took=90s"},
+		{"fractional", time.Second, 1500 * time.Millisecond, "took=1.5s"},
+		{"unset falls back to String", 0, 1500 * time.Millisecond, "took=1.5s"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			h := logutil.NewPrettyHandler(&buf, &logutil.PrettyHandlerOptions{
+				ReplaceAttr:  logutil.RemoveKeys(slog.TimeKey, slog.LevelKey, slog.MessageKey),
+				DisableColor: true,
+				DurationUnit: tt.unit,
+			})
+			r := slog.NewRecord(testTime, slog.LevelInfo, "message", 0)
+			r.AddAttrs(slog.Duration("took", tt.d))
+			if err := h.Handle(context.Background(), r); err != nil {
+				t.Fatal(err)
+			}
+			got := strings.TrimSuffix(buf.String(), "\n")
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrettyHandlerLevelText(t *testing.T) {
+	var buf bytes.Buffer
+	h := logutil.NewPrettyHandler(&buf, &logutil.PrettyHandlerOptions{
+		Level:        slog.LevelDebug,
+		ReplaceAttr:  logutil.RemoveKeys(slog.TimeKey),
+		DisableColor: true,
+		LevelText: func(level slog.Level) string {
+			switch level {
+			case slog.LevelDebug:
+				return "D"
+			case slog.LevelInfo:
+				return "I"
+			case slog.LevelWarn:
+				return "W"
+			case slog.LevelError:
+				return "E"
+			}
+			return level.String()
+		},
+	})
+	logger := slog.New(h)
+	logger.Debug("first")
+	logger.Error("second")
+
+	want := fmt.Sprintf("D %-44s\nE %-44s\n", "first", "second")
+	if got := buf.String(); got != want {
+		t.Errorf("\ngot\n%s\nwant\n%s", got, want)
+	}
+}
+
+// closedPipeWriter fails every write with io.ErrClosedPipe and counts attempts.
+type closedPipeWriter struct {
+	writes int
+}
+
+func (w *closedPipeWriter) Write(p []byte) (int, error) {
+	w.writes++
+	return 0, io.ErrClosedPipe
+}
+
+func TestPrettyHandlerOnWriteError(t *testing.T) {
+	var w closedPipeWriter
+	var calls int
+	h := logutil.NewPrettyHandler(&w, &logutil.PrettyHandlerOptions{
+		OnWriteError: func(err error) bool {
+			calls++
+			return errors.Is(err, io.ErrClosedPipe)
+		},
+	})
+	logger := slog.New(h)
+	logger.Info("first")
+	logger.Info("second")
+	logger.Info("third")
+
+	if w.writes != 1 {
+		t.Errorf("got %d writes, want 1 since the handler should stop after the first failure", w.writes)
+	}
+	if calls != 1 {
+		t.Errorf("got %d OnWriteError calls, want 1", calls)
+	}
+	// Derived handlers should also stop writing since they share the writer.
+	slog.New(h.WithAttrs([]slog.Attr{slog.String("id", "foo")})).Info("fourth")
+	if w.writes != 1 {
+		t.Errorf("got %d writes after derived handler, want 1", w.writes)
+	}
+}
+
+func TestPrettyHandlerSeparators(t *testing.T) {
+	var buf bytes.Buffer
+	h := logutil.NewPrettyHandler(&buf, &logutil.PrettyHandlerOptions{
+		DisableColor: true,
+		ReplaceAttr:  logutil.RemoveKeys(slog.TimeKey),
+		KeyValueSep:  ":",
+		PairSep:      "\t",
+	})
+	logger := slog.New(h)
+	logger.Info("doing stuff", "service", "api", "url", "https://example.com:8080")
+
+	// The url value contains the key-value separator so it must be quoted.
+	want := fmt.Sprintf("INFO \t%-44s\tservice:api\turl:\"https://example.com:8080\"\n", "doing stuff")
+	if got := buf.String(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestPrettyHandlerNoQuote(t *testing.T) {
+	var buf bytes.Buffer
+	h := logutil.NewPrettyHandler(&buf, &logutil.PrettyHandlerOptions{
+		DisableColor: true,
+		ReplaceAttr:  logutil.RemoveKeys(slog.TimeKey),
+		NoQuote: func(a slog.Attr) bool {
+			_, ok := a.Value.Any().(json.RawMessage)
+			return ok
+		},
+	})
+	logger := slog.New(h)
+	raw := json.RawMessage(`{"a": 1, "b": "two"}`)
+	logger.Info("doing stuff", "payload", raw, "note", "has spaces")
+
+	// The raw message should be emitted inline while the regular string
+	// value is still quoted.
+	want := fmt.Sprintf("INFO  %-44s payload=%s note=\"has spaces\"\n", "doing stuff", raw)
+	if got := buf.String(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+// stringerState is a fmt.Stringer used to test stringify's Stringer fallback.
+type stringerState int
+
+func (s stringerState) String() string {
+	if s == 1 {
+		return "running"
+	}
+	return "stopped"
+}
+
+func TestValidatePrettyHandlerOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    *logutil.PrettyHandlerOptions
+		wantErr bool
+	}{
+		{"nil options", nil, false},
+		{"zero options", &logutil.PrettyHandlerOptions{}, false},
+		{"valid duration unit", &logutil.PrettyHandlerOptions{DurationUnit: time.Millisecond}, false},
+		{"unsupported duration unit", &logutil.PrettyHandlerOptions{DurationUnit: 42}, true},
+		{"same separators", &logutil.PrettyHandlerOptions{KeyValueSep: "\t", PairSep: "\t"}, true},
+		{"pair sep matches default key value sep", &logutil.PrettyHandlerOptions{PairSep: "="}, true},
+		{
+			name: "full source with source format",
+			opts: &logutil.PrettyHandlerOptions{
+				FullSource:   true,
+				SourceFormat: func(src *slog.Source) string { return src.File },
+			},
+			wantErr: true,
+		},
+		{"empty column key", &logutil.PrettyHandlerOptions{Columns: []string{"a", ""}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := logutil.ValidatePrettyHandlerOptions(tt.opts)
+			if gotErr := err != nil; gotErr != tt.wantErr {
+				t.Errorf("got error %v, want error %t", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPrettyHandlerNormalizesDurationUnit(t *testing.T) {
+	// An unsupported duration unit should be ignored, falling back to the
+	// default duration formatting instead of rendering empty values.
+	var buf bytes.Buffer
+	h := logutil.NewPrettyHandler(&buf, &logutil.PrettyHandlerOptions{
+		ReplaceAttr:  logutil.RemoveKeys(slog.TimeKey, slog.LevelKey, slog.MessageKey),
+		DisableColor: true,
+		DurationUnit: 42,
+	})
+	r := slog.NewRecord(testTime, slog.LevelInfo, "message", 0)
+	r.AddAttrs(slog.Duration("d", 1500*time.Millisecond))
+	if err := h.Handle(context.Background(), r); err != nil {
+		t.Fatal(err)
+	}
+	want := "d=1.5s\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
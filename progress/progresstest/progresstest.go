@@ -0,0 +1,174 @@
+// Package progresstest provides helpers for testing code that uses the progress package.
+//
+// It lives in a separate package so that the main progress package does not
+// need to import the testing package.
+package progresstest
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/cszatmary/goutils/progress"
+)
+
+// WithTracker returns a context containing a Recorder as its Tracker,
+// along with the Recorder itself so the test can make assertions on it.
+// The context is derived from context.Background.
+func WithTracker(t *testing.T) (context.Context, *Recorder) {
+	t.Helper()
+	r := &Recorder{}
+	return progress.ContextWithTracker(context.Background(), r), r
+}
+
+// Recorder is a progress.Tracker that records all calls made to it
+// so tests can assert on how a tracker was used.
+//
+// A Recorder is safe for use across multiple goroutines.
+// The zero value is a valid Recorder.
+type Recorder struct {
+	mu       sync.Mutex
+	starts   []StartCall
+	stops    int
+	incs     int
+	messages []string // UpdateMessage calls
+	logs     []LogEntry
+}
+
+// StartCall records the arguments of a single Start call.
+type StartCall struct {
+	Msg   string
+	Count int
+}
+
+// LogEntry records a single logged message along with its level and attrs.
+type LogEntry struct {
+	Level string // lowercased level, e.g. "debug", "info", "warn", "error", or "info+2"
+	Msg   string
+	Args  []any
+}
+
+// Starts returns the recorded Start calls in order.
+func (r *Recorder) Starts() []StartCall {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]StartCall(nil), r.starts...)
+}
+
+// Stops returns the number of Stop calls.
+func (r *Recorder) Stops() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.stops
+}
+
+// Incs returns the number of Inc calls.
+func (r *Recorder) Incs() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.incs
+}
+
+// Messages returns the messages from UpdateMessage calls in order.
+func (r *Recorder) Messages() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.messages...)
+}
+
+// Logs returns the recorded log entries in order.
+func (r *Recorder) Logs() []LogEntry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]LogEntry(nil), r.logs...)
+}
+
+func (r *Recorder) Start(msg string, count int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.starts = append(r.starts, StartCall{Msg: msg, Count: count})
+}
+
+func (r *Recorder) Stop() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.stops++
+}
+
+func (r *Recorder) Inc() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.incs++
+}
+
+func (r *Recorder) UpdateMessage(msg string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.messages = append(r.messages, msg)
+}
+
+func (r *Recorder) WithAttrs(args ...any) progress.Logger {
+	// Return a logger that records into the same Recorder with the attrs applied.
+	return &attrRecorder{r: r, attrs: append([]any(nil), args...)}
+}
+
+func (r *Recorder) log(level, msg string, args ...any) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.logs = append(r.logs, LogEntry{Level: level, Msg: msg, Args: args})
+}
+
+func (r *Recorder) Debugf(format string, args ...any) { r.log("debug", fmt.Sprintf(format, args...)) }
+func (r *Recorder) Infof(format string, args ...any)  { r.log("info", fmt.Sprintf(format, args...)) }
+func (r *Recorder) Warnf(format string, args ...any)  { r.log("warn", fmt.Sprintf(format, args...)) }
+func (r *Recorder) Errorf(format string, args ...any) { r.log("error", fmt.Sprintf(format, args...)) }
+func (r *Recorder) Debug(msg string, args ...any)     { r.log("debug", msg, args...) }
+func (r *Recorder) Info(msg string, args ...any)      { r.log("info", msg, args...) }
+func (r *Recorder) Warn(msg string, args ...any)      { r.log("warn", msg, args...) }
+func (r *Recorder) Error(msg string, args ...any)     { r.log("error", msg, args...) }
+func (r *Recorder) Log(level slog.Level, msg string, args ...any) {
+	r.log(levelString(level), msg, args...)
+}
+
+// levelString renders a slog.Level the way LogEntry.Level expects,
+// e.g. "info" for slog.LevelInfo or "info+2" for a custom level.
+func levelString(level slog.Level) string {
+	return strings.ToLower(level.String())
+}
+
+// attrRecorder is a Logger returned by Recorder.WithAttrs. It records logs
+// into the parent Recorder with its attrs prepended to the log args.
+type attrRecorder struct {
+	r     *Recorder
+	attrs []any
+}
+
+func (l *attrRecorder) WithAttrs(args ...any) progress.Logger {
+	return &attrRecorder{r: l.r, attrs: append(append([]any(nil), l.attrs...), args...)}
+}
+
+func (l *attrRecorder) log(level, msg string, args ...any) {
+	l.r.mu.Lock()
+	defer l.r.mu.Unlock()
+	allArgs := append(append([]any(nil), l.attrs...), args...)
+	l.r.logs = append(l.r.logs, LogEntry{Level: level, Msg: msg, Args: allArgs})
+}
+
+func (l *attrRecorder) Debugf(format string, args ...any) {
+	l.log("debug", fmt.Sprintf(format, args...))
+}
+func (l *attrRecorder) Infof(format string, args ...any) { l.log("info", fmt.Sprintf(format, args...)) }
+func (l *attrRecorder) Warnf(format string, args ...any) { l.log("warn", fmt.Sprintf(format, args...)) }
+func (l *attrRecorder) Errorf(format string, args ...any) {
+	l.log("error", fmt.Sprintf(format, args...))
+}
+func (l *attrRecorder) Debug(msg string, args ...any) { l.log("debug", msg, args...) }
+func (l *attrRecorder) Info(msg string, args ...any)  { l.log("info", msg, args...) }
+func (l *attrRecorder) Warn(msg string, args ...any)  { l.log("warn", msg, args...) }
+func (l *attrRecorder) Error(msg string, args ...any) { l.log("error", msg, args...) }
+func (l *attrRecorder) Log(level slog.Level, msg string, args ...any) {
+	l.log(levelString(level), msg, args...)
+}
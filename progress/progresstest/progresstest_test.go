@@ -0,0 +1,78 @@
+package progresstest_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cszatmary/goutils/progress"
+	"github.com/cszatmary/goutils/progress/progresstest"
+)
+
+func TestRecorder(t *testing.T) {
+	ctx, r := progresstest.WithTracker(t)
+	tracker := progress.TrackerFromContext(ctx)
+	tracker.Start("doing stuff", 2)
+	tracker.Inc()
+	tracker.UpdateMessage("halfway")
+	tracker.Inc()
+	tracker.Stop()
+
+	starts := r.Starts()
+	if len(starts) != 1 {
+		t.Fatalf("got %d starts, want 1", len(starts))
+	}
+	want := progresstest.StartCall{Msg: "doing stuff", Count: 2}
+	if starts[0] != want {
+		t.Errorf("got start %+v, want %+v", starts[0], want)
+	}
+	if got := r.Incs(); got != 2 {
+		t.Errorf("got %d incs, want 2", got)
+	}
+	if got := r.Stops(); got != 1 {
+		t.Errorf("got %d stops, want 1", got)
+	}
+	msgs := r.Messages()
+	if len(msgs) != 1 || msgs[0] != "halfway" {
+		t.Errorf("got messages %v, want [halfway]", msgs)
+	}
+}
+
+func TestRecorderLogs(t *testing.T) {
+	_, r := progresstest.WithTracker(t)
+	r.Info("hello", "id", 1)
+	r.WithAttrs("operation", "deploy").Errorf("failed: %s", "oops")
+
+	logs := r.Logs()
+	if len(logs) != 2 {
+		t.Fatalf("got %d logs, want 2", len(logs))
+	}
+	if logs[0].Level != "info" || logs[0].Msg != "hello" {
+		t.Errorf("got log %+v, want info hello", logs[0])
+	}
+	if len(logs[0].Args) != 2 || logs[0].Args[0] != "id" || logs[0].Args[1] != 1 {
+		t.Errorf("got args %v, want [id 1]", logs[0].Args)
+	}
+	if logs[1].Level != "error" || logs[1].Msg != "failed: oops" {
+		t.Errorf("got log %+v, want error failed: oops", logs[1])
+	}
+	if len(logs[1].Args) != 2 || logs[1].Args[0] != "operation" || logs[1].Args[1] != "deploy" {
+		t.Errorf("got args %v, want [operation deploy]", logs[1].Args)
+	}
+}
+
+func TestRecorderInRun(t *testing.T) {
+	ctx, r := progresstest.WithTracker(t)
+	err := progress.Run(ctx, progress.RunOptions{Message: "running"}, func(ctx context.Context) error {
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("want nil error, got %v", err)
+	}
+	starts := r.Starts()
+	if len(starts) != 1 || starts[0].Msg != "running" {
+		t.Errorf("got starts %+v, want one start with message running", starts)
+	}
+	if got := r.Stops(); got != 1 {
+		t.Errorf("got %d stops, want 1", got)
+	}
+}
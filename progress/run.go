@@ -2,6 +2,7 @@ package progress
 
 import (
 	"context"
+	"fmt"
 	"runtime"
 	"time"
 
@@ -24,6 +25,21 @@ type RunOptions struct {
 	// Timeout sets a timeout after which the running function will be cancelled.
 	// Defaults to 10min if omitted.
 	Timeout time.Duration
+	// FailMessage is passed to Tracker.UpdateMessage if the running function
+	// returns an error, before the Tracker is stopped. This makes failures visible
+	// in the output instead of the last message looking like a success.
+	// If omitted, the error's message will be used.
+	FailMessage string
+	// ShowRemaining periodically updates the Tracker's message to show the
+	// remaining time before the operation times out, e.g.
+	// "performing operation (4m12s left)". This can reassure users during
+	// long operations that have a deadline.
+	ShowRemaining bool
+	// LogDuration reports the elapsed time of the operation through the Tracker
+	// once it completes. On success an info log is made containing the duration,
+	// e.g. "performing operation: done in 1.2s". On error the elapsed time is
+	// appended to the failure message.
+	LogDuration bool
 	// TrackerKey can be used to specify a custom context key for retrieving a Tracker.
 	// This should be used if ContextWithTrackerUsingKey was used.
 	// If omitted, the default key will be used.
@@ -60,7 +76,181 @@ func RunT[T any](ctx context.Context, opts RunOptions, fn RunFuncT[T]) (T, error
 	defer tracker.Stop()
 	ctx, cancel := context.WithTimeout(ctx, opts.Timeout)
 	defer cancel()
-	return fn(ctx)
+
+	var remainingDone chan struct{}
+	if opts.ShowRemaining {
+		remainingDone = make(chan struct{})
+		go showRemaining(ctx, tracker, opts, remainingDone)
+	}
+
+	start := time.Now()
+	v, err := fn(ctx)
+	elapsed := time.Since(start).Round(time.Millisecond)
+	if remainingDone != nil {
+		// Stop the remaining-time updates before any final message is written
+		// so the final message always wins.
+		cancel()
+		<-remainingDone
+	}
+	if err != nil {
+		msg := opts.FailMessage
+		if msg == "" {
+			msg = err.Error()
+		}
+		if opts.LogDuration {
+			msg = fmt.Sprintf("%s (after %s)", msg, elapsed)
+		}
+		tracker.UpdateMessage(msg)
+	} else if opts.LogDuration {
+		msg := opts.Message
+		if msg == "" {
+			msg = "operation"
+		}
+		tracker.Infof("%s: done in %s", msg, elapsed)
+	}
+	return v, err
+}
+
+// OperationOptions is used to customize StartOperation.
+// All fields are optional and have defaults.
+type OperationOptions struct {
+	// Tracker is the Tracker to install in the returned context.
+	// If omitted, the Tracker already present in the context is used.
+	Tracker Tracker
+	// Message is the message that will be passed to Tracker.Start.
+	// If omitted no message will be written by the Tracker.
+	Message string
+	// Count is the count passed to Tracker.Start to track the number of operations.
+	// If omitted it will be 0, i.e. no count.
+	Count int
+	// Timeout sets a timeout after which the returned context will be cancelled.
+	// Defaults to 10min if omitted.
+	Timeout time.Duration
+	// TrackerKey can be used to specify a custom context key for storing or
+	// retrieving a Tracker. If omitted, the default key will be used.
+	TrackerKey any
+}
+
+// StartOperation derives a child context set up for a single operation:
+// it installs the tracker, applies the timeout, and starts the tracker.
+// The returned cleanup function stops the tracker and cancels the context,
+// and must be called once the operation is complete.
+//
+// This is the same setup Run performs internally, exposed for callers that
+// want manual control over the operation body.
+//
+//	ctx, done := progress.StartOperation(ctx, progress.OperationOptions{Message: "doing stuff"})
+//	defer done()
+func StartOperation(ctx context.Context, opts OperationOptions) (context.Context, func()) {
+	if opts.Timeout == 0 {
+		// Always provide a timeout to make sure the program doesn't hang and run forever.
+		opts.Timeout = defaultTimeout
+	}
+	tracker := opts.Tracker
+	if tracker == nil {
+		tracker = TrackerFromContextUsingKey(ctx, opts.TrackerKey)
+	} else {
+		ctx = ContextWithTrackerUsingKey(ctx, tracker, opts.TrackerKey)
+	}
+	tracker.Start(opts.Message, opts.Count)
+	ctx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	return ctx, func() {
+		cancel()
+		tracker.Stop()
+	}
+}
+
+// showRemaining periodically updates the tracker's message with the time
+// remaining until ctx's deadline. It runs until ctx is done and closes done
+// when it returns.
+func showRemaining(ctx context.Context, tracker Tracker, opts RunOptions, done chan<- struct{}) {
+	defer close(done)
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return
+	}
+	// Update once a second, or more frequently for short timeouts so at
+	// least a few updates occur.
+	interval := time.Second
+	if d := opts.Timeout / 10; d < interval {
+		interval = d
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			remaining := time.Until(deadline).Round(interval)
+			if remaining < 0 {
+				remaining = 0
+			}
+			tracker.UpdateMessage(fmt.Sprintf("%s (%s left)", opts.Message, remaining))
+		}
+	}
+}
+
+// Step is a single step run by RunSteps.
+type Step struct {
+	// Message is the message the Tracker will display while the step is running.
+	// If omitted the previous message is kept.
+	Message string
+	// Run is the function that performs the step. ctx should be passed to any
+	// operations that take a Context to ensure that timeouts and cancellations
+	// are propagated.
+	Run RunFunc
+}
+
+// RunStepsOptions is used to customize how RunSteps behaves.
+// All fields are optional and have defaults.
+type RunStepsOptions struct {
+	// Message is the message that will be passed to Tracker.Start.
+	// If omitted no message will be written by the Tracker.
+	Message string
+	// Timeout sets a timeout after which the running step will be cancelled.
+	// The timeout applies to the steps as a whole, not each individual step.
+	// Defaults to 10min if omitted.
+	Timeout time.Duration
+	// TrackerKey can be used to specify a custom context key for retrieving a Tracker.
+	// This should be used if ContextWithTrackerUsingKey was used.
+	// If omitted, the default key will be used.
+	TrackerKey any
+}
+
+// RunSteps runs each step in order, updating the Tracker's message with each
+// step's message and incrementing the progress count as steps complete.
+// It is the sequential analogue of RunParallel for ordered multi-step workflows.
+// If a step returns an error, RunSteps stops and returns that error without
+// running the remaining steps.
+//
+// opts can be used to customize the behaviour of RunSteps. See each option for more details.
+func RunSteps(ctx context.Context, opts RunStepsOptions, steps []Step) error {
+	// No-op if there are no steps since we have nothing to run.
+	if len(steps) == 0 {
+		return nil
+	}
+	if opts.Timeout == 0 {
+		// Always provide a timeout to make sure the program doesn't hang and run forever.
+		opts.Timeout = defaultTimeout
+	}
+
+	tracker := TrackerFromContextUsingKey(ctx, opts.TrackerKey)
+	tracker.Start(opts.Message, len(steps))
+	defer tracker.Stop()
+	ctx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+
+	for _, step := range steps {
+		if step.Message != "" {
+			tracker.UpdateMessage(step.Message)
+		}
+		if err := step.Run(ctx); err != nil {
+			return err
+		}
+		tracker.Inc()
+	}
+	return nil
 }
 
 // RunParallelOptions is used to customize how RunParallel behaves.
@@ -88,6 +278,10 @@ type RunParallelOptions struct {
 	//
 	// This option only applies if Count > 1.
 	CancelOnError bool
+	// CountErrors determines whether functions that return an error still increment
+	// the Tracker's progress. By default only successful functions count towards
+	// progress so that cancelled or failed tasks are not displayed as done.
+	CountErrors bool
 	// Timeout sets a timeout after which any running functions will be cancelled.
 	// Defaults to 10min if omitted.
 	Timeout time.Duration
@@ -148,7 +342,9 @@ func RunParallelT[T any](ctx context.Context, opts RunParallelOptions, fn RunPar
 		i := i // https://go.dev/doc/faq#closures_and_goroutines
 		group.Queue(func(ctx context.Context) (T, error) {
 			v, err := fn(ctx, i)
-			tracker.Inc()
+			if err == nil || opts.CountErrors {
+				tracker.Inc()
+			}
 			return v, err
 		})
 	}
@@ -6,12 +6,14 @@ import (
 	"io"
 	"log/slog"
 	"slices"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/cszatmary/goutils/errors"
 	"github.com/cszatmary/goutils/logutil"
 	"github.com/cszatmary/goutils/progress"
+	"github.com/cszatmary/goutils/progress/progresstest"
 )
 
 const errOops errors.String = "oops"
@@ -118,6 +120,59 @@ func TestRunError(t *testing.T) {
 	}
 }
 
+func TestRunFailMessage(t *testing.T) {
+	tests := []struct {
+		name        string
+		failMessage string
+		wantMsg     string
+	}{
+		{"custom message", "operation failed", `"operation failed"`},
+		{"defaults to error", "", "oops"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b bytes.Buffer
+			tracker := newMockTracker(&b)
+			ctx := progress.ContextWithTracker(context.Background(), tracker)
+			err := progress.Run(ctx, progress.RunOptions{
+				Message:     "performing operation",
+				FailMessage: tt.failMessage,
+			}, func(ctx context.Context) error {
+				return errOops
+			})
+			if !errors.Is(err, errOops) {
+				t.Errorf("got err %v, want %v", err, errOops)
+			}
+			want := "level=INFO msg=\"performing operation\"\nlevel=INFO msg=" + tt.wantMsg + "\n"
+			if got := b.String(); got != want {
+				t.Errorf("got logs\n\t%s\nwant\n\t%s", got, want)
+			}
+		})
+	}
+}
+
+func TestRunShowRemaining(t *testing.T) {
+	var b syncBuffer
+	tracker := newMockTracker(&b)
+	ctx := progress.ContextWithTracker(context.Background(), tracker)
+	err := progress.Run(ctx, progress.RunOptions{
+		Message:       "performing operation",
+		Timeout:       200 * time.Millisecond,
+		ShowRemaining: true,
+	}, func(ctx context.Context) error {
+		time.Sleep(100 * time.Millisecond)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// The exact number of updates depends on timing, just make sure at least
+	// one remaining time update was written.
+	if got := b.String(); !strings.Contains(got, "left)") {
+		t.Errorf("got logs\n\t%s\nwant a remaining time update", got)
+	}
+}
+
 func TestRunTError(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -159,6 +214,63 @@ func TestRunTError(t *testing.T) {
 	}
 }
 
+func TestRunSteps(t *testing.T) {
+	var b bytes.Buffer
+	tracker := newMockTracker(&b)
+	ctx := progress.ContextWithTracker(context.Background(), tracker)
+	err := progress.RunSteps(ctx, progress.RunStepsOptions{
+		Message: "performing operation",
+	}, []progress.Step{
+		{Message: "step one", Run: func(ctx context.Context) error { return nil }},
+		{Message: "step two", Run: func(ctx context.Context) error { return nil }},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if tracker.active {
+		t.Error("want tracker to be stopped, but isn't")
+	}
+	if tracker.count != 2 {
+		t.Errorf("got count %d, want 2", tracker.count)
+	}
+	if tracker.i != 2 {
+		t.Errorf("got %d increments, want 2", tracker.i)
+	}
+	want := `level=INFO msg="performing operation"
+level=INFO msg="step one"
+level=INFO msg="step two"
+`
+	if got := b.String(); got != want {
+		t.Errorf("got logs\n\t%s\nwant\n\t%s", got, want)
+	}
+}
+
+func TestRunStepsError(t *testing.T) {
+	tracker := newMockTracker(io.Discard)
+	ctx := progress.ContextWithTracker(context.Background(), tracker)
+	ranThird := false
+	err := progress.RunSteps(ctx, progress.RunStepsOptions{
+		Message: "performing operation",
+	}, []progress.Step{
+		{Message: "step one", Run: func(ctx context.Context) error { return nil }},
+		{Message: "step two", Run: func(ctx context.Context) error { return errOops }},
+		{Message: "step three", Run: func(ctx context.Context) error {
+			ranThird = true
+			return nil
+		}},
+	})
+	if !errors.Is(err, errOops) {
+		t.Errorf("got err %v, want %v", err, errOops)
+	}
+	if ranThird {
+		t.Error("expected third step not to run, but it did")
+	}
+	if tracker.i != 1 {
+		t.Errorf("got %d increments, want 1", tracker.i)
+	}
+}
+
 func TestRunParallel(t *testing.T) {
 	tracker := newMockTracker(io.Discard)
 	ctx := progress.ContextWithTracker(context.Background(), tracker)
@@ -352,6 +464,41 @@ func TestRunParallelTError(t *testing.T) {
 	}
 }
 
+func TestRunParallelCountErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		countErrors bool
+		wantIncs    int
+	}{
+		{"only successful tasks count", false, 2},
+		{"errors count", true, 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracker := newMockTracker(io.Discard)
+			ctx := progress.ContextWithTracker(context.Background(), tracker)
+			err := progress.RunParallel(ctx, progress.RunParallelOptions{
+				Message:     "performing operation",
+				Count:       4,
+				CountErrors: tt.countErrors,
+				// Run serially so incrementing the mock tracker is deterministic.
+				Concurrency: 1,
+			}, func(ctx context.Context, i int) error {
+				if i%2 == 1 {
+					return errOops
+				}
+				return nil
+			})
+			if !errors.Is(err, errOops) {
+				t.Errorf("got err %v, want %v", err, errOops)
+			}
+			if tracker.i != tt.wantIncs {
+				t.Errorf("got %d increments, want %d", tracker.i, tt.wantIncs)
+			}
+		})
+	}
+}
+
 func TestRunParallelMultipleErrors(t *testing.T) {
 	tracker := newMockTracker(io.Discard)
 	ctx := progress.ContextWithTracker(context.Background(), tracker)
@@ -422,3 +569,89 @@ func (t *mockSpinnerTracker) Inc() {
 func (t *mockSpinnerTracker) UpdateMessage(m string) {
 	t.Logger.Info(m)
 }
+
+func TestRunLogDuration(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		ctx, r := progresstest.WithTracker(t)
+		err := progress.Run(ctx, progress.RunOptions{
+			Message:     "performing operation",
+			LogDuration: true,
+		}, func(ctx context.Context) error {
+			time.Sleep(10 * time.Millisecond)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("want nil error, got %v", err)
+		}
+
+		logs := r.Logs()
+		if len(logs) != 1 {
+			t.Fatalf("got %d logs, want 1", len(logs))
+		}
+		if logs[0].Level != "info" {
+			t.Errorf("got level %q, want %q", logs[0].Level, "info")
+		}
+		if !strings.HasPrefix(logs[0].Msg, "performing operation: done in ") {
+			t.Errorf("got log %q, want it to report the elapsed duration", logs[0].Msg)
+		}
+	})
+	t.Run("error", func(t *testing.T) {
+		ctx, r := progresstest.WithTracker(t)
+		err := progress.Run(ctx, progress.RunOptions{
+			Message:     "performing operation",
+			LogDuration: true,
+		}, func(ctx context.Context) error {
+			return errOops
+		})
+		if err != errOops {
+			t.Fatalf("got error %v, want %v", err, errOops)
+		}
+
+		messages := r.Messages()
+		if len(messages) != 1 {
+			t.Fatalf("got %d messages, want 1", len(messages))
+		}
+		if !strings.HasPrefix(messages[0], "oops (after ") {
+			t.Errorf("got message %q, want it to include the elapsed duration", messages[0])
+		}
+	})
+}
+
+func TestStartOperation(t *testing.T) {
+	r := &progresstest.Recorder{}
+	ctx, done := progress.StartOperation(context.Background(), progress.OperationOptions{
+		Tracker: r,
+		Message: "doing stuff",
+		Count:   3,
+		Timeout: time.Minute,
+	})
+
+	if got := progress.TrackerFromContext(ctx); got != progress.Tracker(r) {
+		t.Error("want the tracker to be installed in the returned context")
+	}
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		t.Fatal("want the returned context to have a deadline")
+	}
+	if remaining := time.Until(deadline); remaining > time.Minute {
+		t.Errorf("got deadline %s away, want at most 1m", remaining)
+	}
+	starts := r.Starts()
+	if len(starts) != 1 {
+		t.Fatalf("got %d starts, want 1", len(starts))
+	}
+	if starts[0].Msg != "doing stuff" || starts[0].Count != 3 {
+		t.Errorf("got start %+v, want {doing stuff 3}", starts[0])
+	}
+	if r.Stops() != 0 {
+		t.Errorf("got %d stops before cleanup, want 0", r.Stops())
+	}
+
+	done()
+	if r.Stops() != 1 {
+		t.Errorf("got %d stops, want 1", r.Stops())
+	}
+	if ctx.Err() != context.Canceled {
+		t.Errorf("got ctx error %v, want %v", ctx.Err(), context.Canceled)
+	}
+}
@@ -0,0 +1,44 @@
+package progress
+
+import (
+	"fmt"
+
+	"github.com/cszatmary/goutils/async"
+	"github.com/cszatmary/goutils/errors"
+)
+
+// Summary summarizes the results of a batch of operations.
+// It is created by Summarize.
+type Summary struct {
+	// Succeeded is the number of operations that completed without an error.
+	Succeeded int
+	// Failed is the number of operations that returned an error.
+	Failed int
+	// Errors contains the errors from the failed operations,
+	// in the same order as the results they came from.
+	Errors errors.List
+}
+
+// Summarize tallies a slice of results, e.g. from Group.WaitLax, into a
+// Summary. This gives CLIs a one-liner status for a batch of operations.
+//
+//	results := group.WaitLax(ctx)
+//	fmt.Println(progress.Summarize(results)) // "12 succeeded, 3 failed"
+func Summarize[T any](results []async.Result[T]) Summary {
+	var s Summary
+	for _, r := range results {
+		if r.Err != nil {
+			s.Failed++
+			s.Errors = append(s.Errors, r.Err)
+			continue
+		}
+		s.Succeeded++
+	}
+	return s
+}
+
+// String returns a short human-readable form of the summary,
+// e.g. "12 succeeded, 3 failed".
+func (s Summary) String() string {
+	return fmt.Sprintf("%d succeeded, %d failed", s.Succeeded, s.Failed)
+}
@@ -0,0 +1,54 @@
+package progress_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/cszatmary/goutils/async"
+	"github.com/cszatmary/goutils/progress"
+)
+
+func TestSummarize(t *testing.T) {
+	var g async.Group[int]
+	for i := 0; i < 5; i++ {
+		i := i
+		g.Queue(func(ctx context.Context) (int, error) {
+			if i%2 == 1 {
+				return 0, fmt.Errorf("task %d failed", i)
+			}
+			return i, nil
+		})
+	}
+	results := g.WaitLax(context.Background())
+
+	s := progress.Summarize(results)
+	if s.Succeeded != 3 {
+		t.Errorf("got %d succeeded, want 3", s.Succeeded)
+	}
+	if s.Failed != 2 {
+		t.Errorf("got %d failed, want 2", s.Failed)
+	}
+	if len(s.Errors) != 2 {
+		t.Fatalf("got %d errors, want 2", len(s.Errors))
+	}
+	wantErrs := []string{"task 1 failed", "task 3 failed"}
+	for i, want := range wantErrs {
+		if got := s.Errors[i].Error(); got != want {
+			t.Errorf("got error %q, want %q", got, want)
+		}
+	}
+	if got, want := s.String(), "3 succeeded, 2 failed"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestSummarizeEmpty(t *testing.T) {
+	s := progress.Summarize[int](nil)
+	if s.Succeeded != 0 || s.Failed != 0 || len(s.Errors) != 0 {
+		t.Errorf("got %+v, want zero summary", s)
+	}
+	if got, want := s.String(), "0 succeeded, 0 failed"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -15,6 +15,7 @@ package progress
 
 import (
 	"context"
+	"log/slog"
 )
 
 // Logger represents a structured logger that can log messages at different levels.
@@ -27,6 +28,10 @@ import (
 // The arguments to WithAttrs are expected to be a set of key-pair values representing attributes.
 //
 //	logger.WithAttrs("id", id).Info(...)
+//
+// The Log method logs at an arbitrary level. It exists for cases where the
+// level is computed at runtime, e.g. from parsed subprocess output, and should
+// behave like the fixed-level methods for the corresponding standard levels.
 type Logger interface {
 	WithAttrs(args ...any) Logger
 
@@ -39,6 +44,7 @@ type Logger interface {
 	Info(msg string, args ...any)
 	Warn(msg string, args ...any)
 	Error(msg string, args ...any)
+	Log(level slog.Level, msg string, args ...any)
 }
 
 // Spinner represents a type that can display the progress of an operation
@@ -65,6 +71,27 @@ type Tracker interface {
 // As a bonus use empty struct so the key takes up no memory.
 type trackerKey struct{}
 
+// TrackerKey is a unique comparable key that can be used to store a Tracker in a context.
+// It should be created using NewTrackerKey and can be passed to
+// ContextWithTrackerUsingKey and TrackerFromContextUsingKey.
+//
+// Using a TrackerKey instead of an ad hoc key type makes it easy to have
+// multiple trackers coexist in the same context without key collisions.
+type TrackerKey struct {
+	name string
+	id   *int // pointer to make each created key unique
+}
+
+// NewTrackerKey creates a new TrackerKey that is distinct from all other keys.
+// name is only used for debugging purposes to identify the key.
+func NewTrackerKey(name string) TrackerKey {
+	return TrackerKey{name: name, id: new(int)}
+}
+
+func (k TrackerKey) String() string {
+	return "progress.TrackerKey(" + k.name + ")"
+}
+
 // ContextWithTracker returns a new context with t added to it.
 // The tracker can be retrieved later using TrackerFromContext.
 func ContextWithTracker(ctx context.Context, t Tracker) context.Context {
@@ -109,19 +136,189 @@ func TrackerFromContextUsingKey(ctx context.Context, key any) Tracker {
 	return t
 }
 
+// Sub returns a new context containing a Tracker that forwards to the Tracker in ctx
+// but no-ops Start and Stop. This allows nested operations (e.g. a Run inside a
+// RunParallel function) to log and contribute progress to the parent operation's
+// Tracker without fighting over the spinner.
+//
+// If ctx does not contain a Tracker, the returned context will contain a no-op Tracker.
+func Sub(ctx context.Context) context.Context {
+	return SubUsingKey(ctx, nil)
+}
+
+// SubUsingKey is like Sub but allows for using a custom key.
+// It should be used if ContextWithTrackerUsingKey was used to create a context with a custom key.
+func SubUsingKey(ctx context.Context, key any) context.Context {
+	t := TrackerFromContextUsingKey(ctx, key)
+	return ContextWithTrackerUsingKey(ctx, subTracker{t}, key)
+}
+
+// subTracker wraps a parent Tracker so that nested operations do not
+// restart or stop the parent's spinner.
+type subTracker struct {
+	Tracker
+}
+
+func (subTracker) Start(string, int) {}
+func (subTracker) Stop()             {}
+
+// TrackerWithAttrs returns a Tracker that adds args to every log made through it.
+// The arguments are expected to be a set of key-pair values, see Logger.WithAttrs.
+// Start, Stop, Inc, and UpdateMessage pass through to t unchanged.
+//
+// This is useful for scoping a tracker to a specific operation, e.g. adding
+// an "operation" attr to all logs, without reimplementing the Tracker interface.
+func TrackerWithAttrs(t Tracker, args ...any) Tracker {
+	if len(args) == 0 {
+		return t
+	}
+	return attrTracker{Tracker: t, logger: t.WithAttrs(args...)}
+}
+
+// attrTracker embeds the parent Tracker for the Spinner methods and overrides
+// the Logger methods to go through a Logger that has the attrs applied.
+type attrTracker struct {
+	Tracker
+	logger Logger
+}
+
+func (t attrTracker) WithAttrs(args ...any) Logger      { return t.logger.WithAttrs(args...) }
+func (t attrTracker) Debugf(format string, args ...any) { t.logger.Debugf(format, args...) }
+func (t attrTracker) Infof(format string, args ...any)  { t.logger.Infof(format, args...) }
+func (t attrTracker) Warnf(format string, args ...any)  { t.logger.Warnf(format, args...) }
+func (t attrTracker) Errorf(format string, args ...any) { t.logger.Errorf(format, args...) }
+func (t attrTracker) Debug(msg string, args ...any)     { t.logger.Debug(msg, args...) }
+func (t attrTracker) Info(msg string, args ...any)      { t.logger.Info(msg, args...) }
+func (t attrTracker) Warn(msg string, args ...any)      { t.logger.Warn(msg, args...) }
+func (t attrTracker) Error(msg string, args ...any)     { t.logger.Error(msg, args...) }
+func (t attrTracker) Log(level slog.Level, msg string, args ...any) {
+	t.logger.Log(level, msg, args...)
+}
+
+// MultiTracker returns a Tracker that fans out every method call to all the
+// given trackers, both logs and spinner operations. This allows a single
+// Tracker call to drive multiple displays, e.g. inline progress and a status
+// bar. It is akin to io.MultiWriter but for Trackers.
+func MultiTracker(trackers ...Tracker) Tracker {
+	ts := make([]Tracker, len(trackers))
+	copy(ts, trackers)
+	loggers := make([]Logger, len(ts))
+	for i, t := range ts {
+		loggers[i] = t
+	}
+	return multiTracker{multiLogger{loggers}, ts}
+}
+
+type multiTracker struct {
+	multiLogger
+	trackers []Tracker
+}
+
+func (t multiTracker) Start(msg string, count int) {
+	for _, tt := range t.trackers {
+		tt.Start(msg, count)
+	}
+}
+
+func (t multiTracker) Stop() {
+	for _, tt := range t.trackers {
+		tt.Stop()
+	}
+}
+
+func (t multiTracker) Inc() {
+	for _, tt := range t.trackers {
+		tt.Inc()
+	}
+}
+
+func (t multiTracker) UpdateMessage(msg string) {
+	for _, tt := range t.trackers {
+		tt.UpdateMessage(msg)
+	}
+}
+
+// multiLogger fans out Logger method calls to all the underlying loggers.
+type multiLogger struct {
+	loggers []Logger
+}
+
+func (l multiLogger) WithAttrs(args ...any) Logger {
+	loggers := make([]Logger, len(l.loggers))
+	for i, ll := range l.loggers {
+		loggers[i] = ll.WithAttrs(args...)
+	}
+	return multiLogger{loggers}
+}
+
+func (l multiLogger) Debugf(format string, args ...any) {
+	for _, ll := range l.loggers {
+		ll.Debugf(format, args...)
+	}
+}
+
+func (l multiLogger) Infof(format string, args ...any) {
+	for _, ll := range l.loggers {
+		ll.Infof(format, args...)
+	}
+}
+
+func (l multiLogger) Warnf(format string, args ...any) {
+	for _, ll := range l.loggers {
+		ll.Warnf(format, args...)
+	}
+}
+
+func (l multiLogger) Errorf(format string, args ...any) {
+	for _, ll := range l.loggers {
+		ll.Errorf(format, args...)
+	}
+}
+
+func (l multiLogger) Debug(msg string, args ...any) {
+	for _, ll := range l.loggers {
+		ll.Debug(msg, args...)
+	}
+}
+
+func (l multiLogger) Info(msg string, args ...any) {
+	for _, ll := range l.loggers {
+		ll.Info(msg, args...)
+	}
+}
+
+func (l multiLogger) Warn(msg string, args ...any) {
+	for _, ll := range l.loggers {
+		ll.Warn(msg, args...)
+	}
+}
+
+func (l multiLogger) Error(msg string, args ...any) {
+	for _, ll := range l.loggers {
+		ll.Error(msg, args...)
+	}
+}
+
+func (l multiLogger) Log(level slog.Level, msg string, args ...any) {
+	for _, ll := range l.loggers {
+		ll.Log(level, msg, args...)
+	}
+}
+
 // NoopTracker is a Tracker that no-ops on every method.
 type NoopTracker struct{}
 
-func (t NoopTracker) WithAttrs(...any) Logger { return t }
-func (NoopTracker) Debugf(string, ...any)     {}
-func (NoopTracker) Infof(string, ...any)      {}
-func (NoopTracker) Warnf(string, ...any)      {}
-func (NoopTracker) Errorf(string, ...any)     {}
-func (NoopTracker) Debug(string, ...any)      {}
-func (NoopTracker) Info(string, ...any)       {}
-func (NoopTracker) Warn(string, ...any)       {}
-func (NoopTracker) Error(string, ...any)      {}
-func (NoopTracker) Start(string, int)         {}
-func (NoopTracker) Stop()                     {}
-func (NoopTracker) Inc()                      {}
-func (NoopTracker) UpdateMessage(string)      {}
+func (t NoopTracker) WithAttrs(...any) Logger      { return t }
+func (NoopTracker) Debugf(string, ...any)          {}
+func (NoopTracker) Infof(string, ...any)           {}
+func (NoopTracker) Warnf(string, ...any)           {}
+func (NoopTracker) Errorf(string, ...any)          {}
+func (NoopTracker) Debug(string, ...any)           {}
+func (NoopTracker) Info(string, ...any)            {}
+func (NoopTracker) Warn(string, ...any)            {}
+func (NoopTracker) Error(string, ...any)           {}
+func (NoopTracker) Log(slog.Level, string, ...any) {}
+func (NoopTracker) Start(string, int)              {}
+func (NoopTracker) Stop()                          {}
+func (NoopTracker) Inc()                           {}
+func (NoopTracker) UpdateMessage(string)           {}
@@ -1,11 +1,13 @@
 package progress_test
 
 import (
+	"bytes"
 	"context"
 	"io"
 	"testing"
 
 	"github.com/cszatmary/goutils/progress"
+	"github.com/cszatmary/goutils/progress/progresstest"
 )
 
 func TestTrackerFromContext(t *testing.T) {
@@ -36,6 +38,29 @@ func TestTrackerFromContextWithKey(t *testing.T) {
 	}
 }
 
+func TestTrackerKey(t *testing.T) {
+	tracker1 := newMockTracker(io.Discard)
+	tracker2 := newMockTracker(io.Discard)
+	key1 := progress.NewTrackerKey("first")
+	key2 := progress.NewTrackerKey("second")
+	ctx := progress.ContextWithTrackerUsingKey(context.Background(), tracker1, key1)
+	ctx = progress.ContextWithTrackerUsingKey(ctx, tracker2, key2)
+
+	if got := progress.TrackerFromContextUsingKey(ctx, key1); got != tracker1 {
+		t.Errorf("got %+v, want %+v", got, tracker1)
+	}
+	if got := progress.TrackerFromContextUsingKey(ctx, key2); got != tracker2 {
+		t.Errorf("got %+v, want %+v", got, tracker2)
+	}
+	// Keys with the same name must still be unique.
+	otherKey := progress.NewTrackerKey("first")
+	got := progress.TrackerFromContextUsingKey(ctx, otherKey)
+	want := progress.NoopTracker{}
+	if got != want {
+		t.Errorf("got %T, want %T", got, want)
+	}
+}
+
 func TestTrackerFromContextUsingKeyMissing(t *testing.T) {
 	type customKey struct{}
 	key := customKey{}
@@ -46,6 +71,60 @@ func TestTrackerFromContextUsingKeyMissing(t *testing.T) {
 	}
 }
 
+func TestSub(t *testing.T) {
+	tracker := newMockTracker(io.Discard)
+	ctx := progress.ContextWithTracker(context.Background(), tracker)
+	tracker.Start("parent operation", 2)
+
+	subCtx := progress.Sub(ctx)
+	sub := progress.TrackerFromContext(subCtx)
+	// Start and Stop must not affect the parent spinner.
+	sub.Start("child operation", 5)
+	if tracker.count != 2 {
+		t.Errorf("got count %d, want 2", tracker.count)
+	}
+	sub.Inc()
+	sub.Inc()
+	sub.Stop()
+	if !tracker.active {
+		t.Error("want parent tracker to still be running, but isn't")
+	}
+	if tracker.i != 2 {
+		t.Errorf("got %d increments on parent, want 2", tracker.i)
+	}
+}
+
+func TestTrackerWithAttrs(t *testing.T) {
+	var b bytes.Buffer
+	tracker := newMockTracker(&b)
+	wrapped := progress.TrackerWithAttrs(tracker, "operation", "deploy")
+
+	wrapped.Start("performing operation", 2)
+	wrapped.Info("doing stuff")
+	wrapped.Inc()
+	wrapped.Stop()
+
+	// The spinner methods must pass through to the parent tracker.
+	if tracker.active {
+		t.Error("want tracker to be stopped, but isn't")
+	}
+	if tracker.i != 1 {
+		t.Errorf("got %d increments, want 1", tracker.i)
+	}
+	want := `level=INFO msg="performing operation"
+level=INFO msg="doing stuff" operation=deploy
+`
+	if got := b.String(); got != want {
+		t.Errorf("got logs\n\t%s\nwant\n\t%s", got, want)
+	}
+
+	// NoopTracker must remain a no-op when wrapped.
+	noop := progress.TrackerWithAttrs(progress.NoopTracker{}, "operation", "deploy")
+	noop.Start("performing operation", 1)
+	noop.Info("doing stuff")
+	noop.Stop()
+}
+
 func TestTrackerFromContextUsingKeyInvalidPanic(t *testing.T) {
 	type customKey struct{}
 	key := customKey{}
@@ -58,3 +137,34 @@ func TestTrackerFromContextUsingKeyInvalidPanic(t *testing.T) {
 	}()
 	progress.TrackerFromContextUsingKey(ctx, key)
 }
+
+func TestMultiTracker(t *testing.T) {
+	r1 := &progresstest.Recorder{}
+	r2 := &progresstest.Recorder{}
+	tracker := progress.MultiTracker(r1, r2)
+	tracker.Start("doing stuff", 2)
+	tracker.Inc()
+	tracker.UpdateMessage("halfway")
+	tracker.Stop()
+	tracker.WithAttrs("operation", "deploy").Info("done")
+
+	for i, r := range []*progresstest.Recorder{r1, r2} {
+		starts := r.Starts()
+		if len(starts) != 1 || starts[0].Msg != "doing stuff" || starts[0].Count != 2 {
+			t.Errorf("tracker %d: got starts %+v, want one start of doing stuff with count 2", i, starts)
+		}
+		if got := r.Incs(); got != 1 {
+			t.Errorf("tracker %d: got %d incs, want 1", i, got)
+		}
+		if got := r.Messages(); len(got) != 1 || got[0] != "halfway" {
+			t.Errorf("tracker %d: got messages %v, want [halfway]", i, got)
+		}
+		if got := r.Stops(); got != 1 {
+			t.Errorf("tracker %d: got %d stops, want 1", i, got)
+		}
+		logs := r.Logs()
+		if len(logs) != 1 || logs[0].Msg != "done" {
+			t.Errorf("tracker %d: got logs %+v, want one done log", i, logs)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package progress
+
+import (
+	"bufio"
+	"io"
+	"log/slog"
+	"runtime"
+)
+
+// TrackerWriter returns an io.Writer that writes each line as a log on t
+// at the given level. This makes it possible to hand a plain io.Writer to code
+// that only knows how to write bytes and have its output show up as tracker logs.
+//
+// Partial lines are buffered until a newline is written.
+// It is the caller's responsibility to close the returned io.WriteCloser in order
+// to free resources.
+func TrackerWriter(t Tracker, level slog.Level) io.WriteCloser {
+	pr, pw := io.Pipe()
+	var logFunc func(string, ...any)
+	switch level {
+	case slog.LevelDebug:
+		logFunc = t.Debug
+	case slog.LevelInfo:
+		logFunc = t.Info
+	case slog.LevelWarn:
+		logFunc = t.Warn
+	case slog.LevelError:
+		logFunc = t.Error
+	default:
+		logFunc = func(s string, a ...any) {
+			t.Log(level, s, a...)
+		}
+	}
+	go func() {
+		s := bufio.NewScanner(pr)
+		for s.Scan() {
+			logFunc(s.Text())
+		}
+		if err := s.Err(); err != nil {
+			t.Error("Error while reading from Writer", "err", err)
+		}
+		pr.Close()
+	}()
+	runtime.SetFinalizer(pw, (*io.PipeWriter).Close)
+	return pw
+}
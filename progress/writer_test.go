@@ -0,0 +1,59 @@
+package progress_test
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cszatmary/goutils/progress"
+)
+
+// syncBuffer is a bytes.Buffer that is safe for concurrent use.
+// The logs are written on a separate goroutine by TrackerWriter so the
+// buffer needs to be synchronized.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(data []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(data)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestTrackerWriter(t *testing.T) {
+	var b syncBuffer
+	tracker := newMockTracker(&b)
+	w := progress.TrackerWriter(tracker, slog.LevelInfo)
+	// Write a full line and then a line in two parts to make sure
+	// partial lines are buffered.
+	io.WriteString(w, "first line\n")
+	io.WriteString(w, "second ")
+	io.WriteString(w, "line\n")
+	w.Close()
+
+	want := `level=INFO msg="first line"
+level=INFO msg="second line"
+`
+	// Allow some time for the logging goroutine to finish.
+	var got string
+	for i := 0; i < 100; i++ {
+		if got = b.String(); got == want {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if got != want {
+		t.Errorf("got logs\n\t%s\nwant\n\t%s", got, want)
+	}
+}
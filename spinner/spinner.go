@@ -56,6 +56,52 @@ type Spinner struct {
 	// these will be written on each call of erase
 	msgBuf      bytes.Buffer
 	persistMsgs bool
+	growCount   bool
+	rawWrite    bool
+	// width to right align the progress counter to, 0 means no alignment
+	rightAlignWidth int
+	// minimum time between displayed message changes, 0 means no throttling
+	minUpdateInterval time.Duration
+	// when the displayed message last changed, used for throttling
+	lastMsgTime time.Time
+	// latest message waiting to be displayed once the interval has elapsed
+	pendingMsg string
+	// number of recent messages kept visible above the spinner line,
+	// 0 disables history
+	historySize int
+	// the most recent messages, oldest first
+	history []string
+	// number of history lines drawn on the last frame
+	drawnHistory int
+	// historyFallback reports that history should degrade to plainly
+	// persisting messages because the writer is not a terminal
+	historyFallback bool
+	// observer is invoked on each state transition, see WithObserver
+	observer func(e SpinnerEvent)
+	// sanitizer is applied to every message before display, see WithSanitizer
+	sanitizer func(m string) string
+}
+
+// SpinnerEventType identifies the state transition that produced a SpinnerEvent.
+type SpinnerEventType int
+
+const (
+	EventStart SpinnerEventType = iota
+	EventStop
+	EventInc
+	EventUpdateMessage
+)
+
+// SpinnerEvent describes a single spinner state transition.
+// It is passed to the observer registered with WithObserver.
+type SpinnerEvent struct {
+	// Type is the transition that produced the event.
+	Type SpinnerEventType
+	// Msg is the spinner's message after the transition.
+	Msg string
+	// Completed and Count are the spinner's progress after the transition.
+	Completed int
+	Count     int
 }
 
 // New creates a new spinner instance using the given options.
@@ -135,6 +181,93 @@ func WithPersistMessages(b bool) Option {
 	}
 }
 
+// WithGrowCount sets whether the count should grow to accommodate extra Inc calls.
+// By default the progress is clamped, i.e. once completed reaches the count further
+// Inc calls do nothing. This can hide bugs where Inc is called more times than expected.
+// If growing is enabled, the count is increased instead so the overflow is visible.
+func WithGrowCount(b bool) Option {
+	return func(s *Spinner) {
+		s.growCount = b
+	}
+}
+
+// WithRawWrite sets whether data written via Write should be buffered verbatim.
+// By default a newline is inserted whenever buffered data is flushed, which
+// corrupts output for callers streaming partial lines, e.g. a progress byte
+// counter written in fragments. If raw writes are enabled, only complete lines
+// are flushed during the animation and partial lines stay buffered until their
+// newline arrives, making Write behave like a normal line-buffered writer.
+// Any remaining partial line is flushed when the spinner is stopped.
+func WithRawWrite(b bool) Option {
+	return func(s *Spinner) {
+		s.rawWrite = b
+	}
+}
+
+// WithRightAlignCount sets a target line width and causes the progress counter
+// to be right aligned so it ends at that width, with the message left aligned.
+// This looks polished on wide terminals where the counter otherwise sits
+// directly after the message.
+//
+// If the message is too long for the available space it is truncated so the
+// counter always remains visible. A width of zero or less disables alignment.
+func WithRightAlignCount(width int) Option {
+	return func(s *Spinner) {
+		s.rightAlignWidth = width
+	}
+}
+
+// WithMinUpdateInterval sets the minimum amount of time between displayed
+// message changes. If UpdateMessage is called again before the interval has
+// elapsed, the new message is held and displayed once the interval passes,
+// with only the latest message kept. This prevents flicker when UpdateMessage
+// is called in a tight loop. The final message before Stop is always displayed.
+// By default messages are not throttled.
+func WithMinUpdateInterval(d time.Duration) Option {
+	return func(s *Spinner) {
+		s.minUpdateInterval = d
+	}
+}
+
+// WithHistory causes the spinner to keep the last n messages visible above
+// the animated line, like build tools that show recently completed items.
+// Whenever the message changes, the previous message scrolls into the history
+// block which is redrawn on each frame. A value of zero or less disables history.
+//
+// Redrawing requires terminal control sequences, so if the writer is not a
+// terminal (or on Windows) history degrades to plain output where each
+// superseded message is printed on its own line, as with WithPersistMessages.
+func WithHistory(n int) Option {
+	return func(s *Spinner) {
+		s.historySize = n
+	}
+}
+
+// WithSanitizer sets a function that is applied to every message before it is
+// displayed, including the start and stop messages. This allows secrets, e.g.
+// a token embedded in a URL, to be scrubbed centrally instead of at every
+// call site, preventing accidental leakage in transcripts and CI logs.
+//
+// The sanitizer is called while the spinner's lock is held, so it must be
+// quick and must not call back into the spinner.
+func WithSanitizer(fn func(m string) string) Option {
+	return func(s *Spinner) {
+		s.sanitizer = fn
+	}
+}
+
+// WithObserver sets a function that is invoked on each spinner state
+// transition: Start, Stop, Inc, and UpdateMessage. This provides a clean hook
+// for tests and metrics without scraping the spinner's text output.
+//
+// The observer is called while the spinner's lock is held, so it must be
+// quick and must not call back into the spinner.
+func WithObserver(fn func(e SpinnerEvent)) Option {
+	return func(s *Spinner) {
+		s.observer = fn
+	}
+}
+
 // Start starts the spinner.
 // If the spinner is already running, Start does nothing.
 func (s *Spinner) Start() {
@@ -144,7 +277,13 @@ func (s *Spinner) Start() {
 		return
 	}
 	s.active = true
+	if s.historySize > 0 && (runtime.GOOS == "windows" || !isTerminal(s.w)) {
+		// Can't redraw the history block, degrade to persisting messages.
+		s.historyFallback = true
+		s.persistMsgs = true
+	}
 	s.setMsg(s.startMsg)
+	s.notify(EventStart)
 	s.mu.Unlock()
 	go s.run()
 }
@@ -160,17 +299,34 @@ func (s *Spinner) Stop() {
 
 	s.active = false
 	s.stopChan <- struct{}{}
+	// The final message always wins, so apply any throttled message now.
+	if s.pendingMsg != "" {
+		s.setMsg(s.pendingMsg)
+		s.pendingMsg = ""
+	}
 	// Persist last msg before we do the final erase.
 	// Need to do this manually since we aren't using setMsg
 	s.persistMsg()
 	s.erase()
+	// If raw writes are enabled, erase leaves any partial line buffered.
+	// Flush it now with a newline so no output is lost.
+	if s.rawWrite && s.msgBuf.Len() > 0 {
+		if s.msgBuf.Bytes()[s.msgBuf.Len()-1] != '\n' {
+			s.msgBuf.WriteByte('\n')
+		}
+		_, _ = s.msgBuf.WriteTo(s.w)
+	}
 	if s.stopMsg != "" {
+		if s.sanitizer != nil {
+			s.stopMsg = s.sanitizer(s.stopMsg)
+		}
 		// Make sure there's a trailing newline
-		if s.stopMsg[len(s.stopMsg)-1] != '\n' {
+		if s.stopMsg != "" && s.stopMsg[len(s.stopMsg)-1] != '\n' {
 			s.stopMsg += "\n"
 		}
 		fmt.Fprint(s.w, s.stopMsg)
 	}
+	s.notify(EventStop)
 }
 
 // Inc increments the progress of the spinner. If the spinner
@@ -186,10 +342,22 @@ func (s *Spinner) IncWithMessage(m string) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	if s.completed >= s.count {
-		return
+		if !s.growCount {
+			return
+		}
+		// Grow the count so the extra increment is visible instead of silently dropped.
+		s.count++
 	}
 	s.completed++
 	s.setMsg(m)
+	s.notify(EventInc)
+}
+
+// Progress returns the number of completed items and the total count.
+func (s *Spinner) Progress() (completed, count int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.completed, s.count
 }
 
 // IncWithMessagef increments the progress of the spinner and updates
@@ -200,10 +368,18 @@ func (s *Spinner) IncWithMessagef(format string, args ...any) {
 }
 
 // UpdateMessage changes the current message being shown by the spinner.
+// If a minimum update interval was set with WithMinUpdateInterval and it has
+// not yet elapsed, the message is held and displayed once the interval passes.
 func (s *Spinner) UpdateMessage(m string) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	if s.minUpdateInterval > 0 && time.Since(s.lastMsgTime) < s.minUpdateInterval {
+		s.pendingMsg = m
+		return
+	}
 	s.setMsg(m)
+	s.lastMsgTime = time.Now()
+	s.notify(EventUpdateMessage)
 }
 
 // setMsg sets the spinner message to m. If m is longer then s.maxMsgLen it will
@@ -213,6 +389,12 @@ func (s *Spinner) setMsg(m string) {
 	if m == "" {
 		return
 	}
+	if s.sanitizer != nil {
+		m = s.sanitizer(m)
+		if m == "" {
+			return
+		}
+	}
 	// Make sure there is no trailing newline or it will mess up the spinner
 	if m[len(m)-1] == '\n' {
 		m = m[:len(m)-1]
@@ -227,9 +409,39 @@ func (s *Spinner) setMsg(m string) {
 		m = " " + m
 	}
 	s.persistMsg()
+	s.pushHistory()
 	s.msg = m
 }
 
+// pushHistory adds the current message to the history block before it is
+// replaced. The caller must already hold s.lock.
+func (s *Spinner) pushHistory() {
+	if s.historySize <= 0 || s.historyFallback || s.msg == "" {
+		return
+	}
+	// Drop the leading pad space added by setMsg.
+	s.history = append(s.history, s.msg[1:])
+	if len(s.history) > s.historySize {
+		s.history = s.history[1:]
+	}
+}
+
+// notify invokes the observer, if any, with the spinner's current state.
+// The caller must already hold s.lock.
+func (s *Spinner) notify(typ SpinnerEventType) {
+	if s.observer == nil {
+		return
+	}
+	// Trim the leading pad space added by setMsg so observers see the
+	// message as it was given.
+	s.observer(SpinnerEvent{
+		Type:      typ,
+		Msg:       strings.TrimPrefix(s.msg, " "),
+		Completed: s.completed,
+		Count:     s.count,
+	})
+}
+
 // persistMsg will handle persisting msg if required. The caller must already hold s.lock.
 func (s *Spinner) persistMsg() {
 	if !s.persistMsgs || s.msg == "" {
@@ -257,6 +469,24 @@ func (s *Spinner) Write(p []byte) (int, error) {
 	return s.msgBuf.Write(p)
 }
 
+// DrainBuffer takes and returns the contents of the spinner's internal write buffer
+// without writing them to the spinner's writer. The buffer is reset, so the
+// returned bytes will not be written during the spinner animation.
+//
+// This can be used to redirect buffered output elsewhere on shutdown, or by
+// tests to assert exactly what was written to the spinner.
+func (s *Spinner) DrainBuffer() []byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.msgBuf.Len() == 0 {
+		return nil
+	}
+	b := make([]byte, s.msgBuf.Len())
+	copy(b, s.msgBuf.Bytes())
+	s.msgBuf.Reset()
+	return b
+}
+
 // run runs the spinner. It should be called in a separate goroutine because
 // it will run forever until it receives a value on s.stopChan.
 func (s *Spinner) run() {
@@ -266,16 +496,29 @@ func (s *Spinner) run() {
 			case <-s.stopChan:
 				return
 			default:
+				frameStart := time.Now()
 				s.mu.Lock()
 				if !s.active {
 					s.mu.Unlock()
 					return
 				}
+				// Display a throttled message if the interval has elapsed.
+				if s.pendingMsg != "" && time.Since(s.lastMsgTime) >= s.minUpdateInterval {
+					s.setMsg(s.pendingMsg)
+					s.pendingMsg = ""
+					s.lastMsgTime = time.Now()
+				}
 				s.erase()
+				s.drawHistory()
 
 				line := fmt.Sprintf("\r%s%s ", frames[i], s.msg)
 				if s.count > 1 {
-					line += fmt.Sprintf("(%d/%d) ", s.completed, s.count)
+					if s.rightAlignWidth > 0 {
+						counter := fmt.Sprintf("(%d/%d)", s.completed, s.count)
+						line = "\r" + rightAlign(frames[i]+s.msg, counter, s.rightAlignWidth)
+					} else {
+						line += fmt.Sprintf("(%d/%d) ", s.completed, s.count)
+					}
 				}
 				fmt.Fprint(s.w, line)
 				s.lastOutput = line
@@ -284,12 +527,77 @@ func (s *Spinner) run() {
 				d := s.interval
 
 				s.mu.Unlock()
-				time.Sleep(d)
+				// Account for the time spent writing the frame so the animation
+				// cadence stays consistent even if the writer is slow.
+				if elapsed := time.Since(frameStart); elapsed < d {
+					time.Sleep(d - elapsed)
+				}
 			}
 		}
 	}
 }
 
+// rightAlign lays out left and right so that right ends exactly at width.
+// If left does not fit in the available space it is truncated, never right,
+// so the counter always remains visible. Widths are measured in runes.
+func rightAlign(left, right string, width int) string {
+	rightLen := utf8.RuneCountInString(right)
+	// If the counter doesn't even fit in the width, alignment is impossible;
+	// fall back to the unaligned layout instead of panicking on negative padding.
+	if width < rightLen+1 {
+		return left + " " + right
+	}
+	// Leave at least one space between the message and the counter.
+	avail := width - rightLen - 1
+	if utf8.RuneCountInString(left) > avail {
+		const ellipses = "..."
+		rs := []rune(left)
+		switch {
+		case avail > len(ellipses):
+			left = string(rs[:avail-len(ellipses)]) + ellipses
+		case avail > 0:
+			left = string(rs[:avail])
+		default:
+			left = ""
+		}
+	}
+	pad := width - utf8.RuneCountInString(left) - rightLen
+	return left + strings.Repeat(" ", pad) + right
+}
+
+// drawHistory redraws the block of recent messages above the spinner line.
+// It erases the lines drawn on the previous frame and rewrites the current
+// history, so the block updates in place as messages scroll through it.
+// The caller must already hold s.lock and have called erase first so the
+// cursor is at the start of the spinner line.
+func (s *Spinner) drawHistory() {
+	if s.historySize <= 0 || s.historyFallback || len(s.history) == 0 {
+		return
+	}
+	// Move up over the previously drawn history lines, clearing each.
+	for i := 0; i < s.drawnHistory; i++ {
+		fmt.Fprint(s.w, "\033[1A\033[2K")
+	}
+	fmt.Fprint(s.w, "\r")
+	for _, m := range s.history {
+		fmt.Fprintf(s.w, "%s\033[K\n", m)
+	}
+	s.drawnHistory = len(s.history)
+}
+
+// isTerminal reports whether w is a terminal device.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return fi.Mode()&os.ModeCharDevice != 0
+}
+
 // erase deletes written characters. The caller must already hold s.lock.
 func (s *Spinner) erase() {
 	n := utf8.RuneCountInString(s.lastOutput)
@@ -306,11 +614,22 @@ func (s *Spinner) erase() {
 	}
 
 	if s.msgBuf.Len() > 0 {
-		if s.msgBuf.Bytes()[s.msgBuf.Len()-1] != '\n' {
-			s.msgBuf.WriteByte('\n')
+		if s.rawWrite {
+			// Only flush complete lines. A partial line stays buffered until its
+			// newline arrives so streamed fragments aren't split.
+			b := s.msgBuf.Bytes()
+			if i := bytes.LastIndexByte(b, '\n'); i >= 0 {
+				// Ignore error because there's nothing we can really do about it
+				_, _ = s.w.Write(b[:i+1])
+				s.msgBuf.Next(i + 1)
+			}
+		} else {
+			if s.msgBuf.Bytes()[s.msgBuf.Len()-1] != '\n' {
+				s.msgBuf.WriteByte('\n')
+			}
+			// Ignore error because there's nothing we can really do about it
+			_, _ = s.msgBuf.WriteTo(s.w)
 		}
-		// Ignore error because there's nothing we can really do about it
-		_, _ = s.msgBuf.WriteTo(s.w)
 	}
 	s.lastOutput = ""
 }
@@ -22,6 +22,47 @@ func (b *syncBuffer) Write(data []byte) (int, error) {
 	return b.Buffer.Write(data)
 }
 
+// slowBuffer delays each write of a spinner frame to simulate a slow writer.
+type slowBuffer struct {
+	syncBuffer
+	delay time.Duration
+}
+
+func (b *slowBuffer) Write(data []byte) (int, error) {
+	// Only delay frame writes, not the erase sequences, so the delay
+	// per frame is deterministic.
+	if bytes.ContainsRune(data, '⠋') || bytes.ContainsRune(data, '⠙') ||
+		bytes.ContainsRune(data, '⠹') || bytes.ContainsRune(data, '⠸') ||
+		bytes.ContainsRune(data, '⠼') || bytes.ContainsRune(data, '⠴') ||
+		bytes.ContainsRune(data, '⠦') || bytes.ContainsRune(data, '⠧') ||
+		bytes.ContainsRune(data, '⠇') || bytes.ContainsRune(data, '⠏') {
+		time.Sleep(b.delay)
+	}
+	return b.syncBuffer.Write(data)
+}
+
+func TestSpinnerFrameTiming(t *testing.T) {
+	// The time spent writing a frame should count towards the interval,
+	// otherwise the animation drifts when the writer is slow.
+	out := &slowBuffer{delay: 60 * time.Millisecond}
+	s := spinner.New(spinner.WithWriter(out), spinner.WithInterval(100*time.Millisecond))
+	s.Start()
+	time.Sleep(650 * time.Millisecond)
+	s.Stop()
+
+	out.Lock()
+	defer out.Unlock()
+	frames := strings.Count(out.String(), "\r⠋") + strings.Count(out.String(), "\r⠙") +
+		strings.Count(out.String(), "\r⠹") + strings.Count(out.String(), "\r⠸") +
+		strings.Count(out.String(), "\r⠼") + strings.Count(out.String(), "\r⠴") +
+		strings.Count(out.String(), "\r⠦") + strings.Count(out.String(), "\r⠧")
+	// With a 100ms interval and a 60ms write, an uncompensated loop would only
+	// render ~4 frames in 650ms. Compensated it should manage at least 5.
+	if frames < 5 {
+		t.Errorf("got %d frames, want at least 5", frames)
+	}
+}
+
 func TestSpinner(t *testing.T) {
 	out := &syncBuffer{}
 	s := spinner.New(spinner.WithWriter(out))
@@ -288,3 +329,320 @@ func containsAll(s string, chars string) bool {
 	}
 	return true
 }
+
+func TestSpinnerProgress(t *testing.T) {
+	tests := []struct {
+		name          string
+		opts          []spinner.Option
+		incs          int
+		wantCompleted int
+		wantCount     int
+	}{
+		{
+			name:          "clamp",
+			opts:          []spinner.Option{spinner.WithCount(3)},
+			incs:          5,
+			wantCompleted: 3,
+			wantCount:     3,
+		},
+		{
+			name:          "grow",
+			opts:          []spinner.Option{spinner.WithCount(3), spinner.WithGrowCount(true)},
+			incs:          5,
+			wantCompleted: 5,
+			wantCount:     5,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := spinner.New(tt.opts...)
+			for i := 0; i < tt.incs; i++ {
+				s.Inc()
+			}
+			completed, count := s.Progress()
+			if completed != tt.wantCompleted {
+				t.Errorf("got completed %d, want %d", completed, tt.wantCompleted)
+			}
+			if count != tt.wantCount {
+				t.Errorf("got count %d, want %d", count, tt.wantCount)
+			}
+		})
+	}
+}
+
+func TestSpinnerRightAlignCount(t *testing.T) {
+	const width = 40
+	out := &syncBuffer{}
+	s := spinner.New(
+		spinner.WithInterval(10*time.Millisecond),
+		spinner.WithWriter(out),
+		spinner.WithStartMessage("Cloning repos"),
+		spinner.WithCount(3),
+		spinner.WithRightAlignCount(width),
+	)
+	s.Start()
+	time.Sleep(15 * time.Millisecond)
+	s.Stop()
+
+	// wait a bit because the spinner still has to erase before stopping
+	time.Sleep(25 * time.Millisecond)
+	got := out.String()
+
+	// The counter should be padded so it ends at exactly the target width.
+	want := "⠋ Cloning repos" + strings.Repeat(" ", 20) + "(0/3)"
+	if !strings.Contains(got, want) {
+		t.Errorf("got %q, want to contain %q", got, want)
+	}
+}
+
+func TestSpinnerRightAlignCountTruncates(t *testing.T) {
+	const width = 20
+	out := &syncBuffer{}
+	s := spinner.New(
+		spinner.WithInterval(10*time.Millisecond),
+		spinner.WithWriter(out),
+		spinner.WithStartMessage("This is a very long message"),
+		spinner.WithCount(3),
+		spinner.WithRightAlignCount(width),
+	)
+	s.Start()
+	time.Sleep(15 * time.Millisecond)
+	s.Stop()
+
+	// wait a bit because the spinner still has to erase before stopping
+	time.Sleep(25 * time.Millisecond)
+	got := out.String()
+
+	// The message should be truncated, not the counter.
+	want := "⠋ This is a... (0/3)"
+	if !strings.Contains(got, want) {
+		t.Errorf("got %q, want to contain %q", got, want)
+	}
+}
+
+func TestSpinnerDrainBuffer(t *testing.T) {
+	out := &syncBuffer{}
+	// Don't start the spinner so nothing is flushed to out.
+	s := spinner.New(spinner.WithWriter(out))
+	fmt.Fprintf(s, "debug stuff 1\n")
+	fmt.Fprintf(s, "debug stuff 2\n")
+
+	got := s.DrainBuffer()
+	want := "debug stuff 1\ndebug stuff 2\n"
+	if string(got) != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	// The buffer should now be empty.
+	if got := s.DrainBuffer(); got != nil {
+		t.Errorf("got %q, want nil", got)
+	}
+	if out.String() != "" {
+		t.Errorf("got %q written to writer, want nothing", out.String())
+	}
+}
+
+func TestSpinnerRawWrite(t *testing.T) {
+	out := &syncBuffer{}
+	s := spinner.New(
+		spinner.WithInterval(10*time.Millisecond),
+		spinner.WithWriter(out),
+		spinner.WithStartMessage("Downloading"),
+		spinner.WithRawWrite(true),
+	)
+	s.Start()
+	// Write a line in two fragments with frames rendered inbetween.
+	fmt.Fprint(s, "progress: 5")
+	time.Sleep(25 * time.Millisecond)
+	fmt.Fprint(s, "0%\n")
+	time.Sleep(25 * time.Millisecond)
+	s.Stop()
+
+	// wait a bit because the spinner still has to erase before stopping
+	time.Sleep(25 * time.Millisecond)
+	got := out.String()
+
+	// The fragments should have been joined into a single line.
+	if !strings.Contains(got, "progress: 50%\n") {
+		t.Errorf("got %q, want to contain %q", got, "progress: 50%\n")
+	}
+	if strings.Contains(got, "progress: 5\n") {
+		t.Errorf("got %q, want to not contain %q", got, "progress: 5\n")
+	}
+}
+
+func TestSpinnerRawWriteFlushOnStop(t *testing.T) {
+	out := &syncBuffer{}
+	s := spinner.New(
+		spinner.WithInterval(10*time.Millisecond),
+		spinner.WithWriter(out),
+		spinner.WithRawWrite(true),
+	)
+	s.Start()
+	fmt.Fprint(s, "no trailing newline")
+	s.Stop()
+
+	time.Sleep(25 * time.Millisecond)
+	if got := out.String(); !strings.Contains(got, "no trailing newline\n") {
+		t.Errorf("got %q, want to contain %q", got, "no trailing newline\n")
+	}
+}
+
+func TestSpinnerMinUpdateInterval(t *testing.T) {
+	buf := &syncBuffer{}
+	s := spinner.New(
+		spinner.WithWriter(buf),
+		spinner.WithStartMessage("starting"),
+		spinner.WithPersistMessages(true),
+		// Use a long interval so everything after the first update is coalesced.
+		spinner.WithMinUpdateInterval(time.Minute),
+	)
+	s.Start()
+	s.UpdateMessage("first")
+	s.UpdateMessage("second")
+	s.UpdateMessage("third")
+	time.Sleep(150 * time.Millisecond)
+	s.Stop()
+
+	buf.Lock()
+	defer buf.Unlock()
+	out := buf.String()
+	if !strings.Contains(out, "first\n") {
+		t.Errorf("got output %q, want it to contain %q", out, "first\n")
+	}
+	if strings.Contains(out, "second") {
+		t.Errorf("got output %q, want intermediate message %q coalesced away", out, "second")
+	}
+	if !strings.Contains(out, "third\n") {
+		t.Errorf("got output %q, want final message %q displayed", out, "third\n")
+	}
+}
+
+func TestSpinnerObserver(t *testing.T) {
+	var mu sync.Mutex
+	var events []spinner.SpinnerEvent
+	s := spinner.New(
+		spinner.WithWriter(&syncBuffer{}),
+		spinner.WithStartMessage("working"),
+		spinner.WithCount(2),
+		spinner.WithObserver(func(e spinner.SpinnerEvent) {
+			mu.Lock()
+			defer mu.Unlock()
+			events = append(events, e)
+		}),
+	)
+	s.Start()
+	s.IncWithMessage("step one done")
+	s.UpdateMessage("almost there")
+	s.Inc()
+	s.Stop()
+
+	mu.Lock()
+	defer mu.Unlock()
+	want := []spinner.SpinnerEvent{
+		{Type: spinner.EventStart, Msg: "working", Completed: 0, Count: 2},
+		{Type: spinner.EventInc, Msg: "step one done", Completed: 1, Count: 2},
+		{Type: spinner.EventUpdateMessage, Msg: "almost there", Completed: 1, Count: 2},
+		{Type: spinner.EventInc, Msg: "almost there", Completed: 2, Count: 2},
+		{Type: spinner.EventStop, Msg: "almost there", Completed: 2, Count: 2},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("got %d events, want %d: %+v", len(events), len(want), events)
+	}
+	for i, w := range want {
+		if events[i] != w {
+			t.Errorf("event %d: got %+v, want %+v", i, events[i], w)
+		}
+	}
+}
+
+func TestSpinnerSanitizer(t *testing.T) {
+	buf := &syncBuffer{}
+	redactToken := func(m string) string {
+		if i := strings.Index(m, "token="); i >= 0 {
+			end := strings.IndexByte(m[i:], '&')
+			if end < 0 {
+				end = len(m) - i
+			}
+			m = m[:i] + "token=REDACTED" + m[i+end:]
+		}
+		return m
+	}
+	s := spinner.New(
+		spinner.WithWriter(buf),
+		spinner.WithPersistMessages(true),
+		spinner.WithSanitizer(redactToken),
+		spinner.WithStopMessage("fetched https://example.com?token=hunter2"),
+	)
+	s.Start()
+	s.UpdateMessage("fetching https://example.com?token=hunter2&page=1")
+	time.Sleep(150 * time.Millisecond)
+	s.Stop()
+
+	buf.Lock()
+	defer buf.Unlock()
+	out := buf.String()
+	if strings.Contains(out, "hunter2") {
+		t.Errorf("got output %q, want the token redacted", out)
+	}
+	if !strings.Contains(out, "fetching https://example.com?token=REDACTED&page=1") {
+		t.Errorf("got output %q, want the sanitized update message", out)
+	}
+	if !strings.Contains(out, "fetched https://example.com?token=REDACTED") {
+		t.Errorf("got output %q, want the sanitized stop message", out)
+	}
+}
+
+func TestSpinnerHistory(t *testing.T) {
+	// A bytes buffer is not a terminal, so history degrades to plain output
+	// where each superseded message is persisted on its own line. The last n
+	// messages must remain visible in the output.
+	buf := &syncBuffer{}
+	s := spinner.New(
+		spinner.WithInterval(10*time.Millisecond),
+		spinner.WithWriter(buf),
+		spinner.WithStartMessage("building"),
+		spinner.WithHistory(2),
+	)
+	s.Start()
+	for _, m := range []string{"compiled a", "compiled b", "compiled c"} {
+		time.Sleep(15 * time.Millisecond)
+		s.UpdateMessage(m)
+	}
+	s.Stop()
+
+	// wait a bit because the spinner still has to erase before stopping
+	time.Sleep(50 * time.Millisecond)
+	got := buf.String()
+	wantMsgs := []string{"compiled b\n", "compiled c\n"}
+	for _, wantMsg := range wantMsgs {
+		if !strings.Contains(got, wantMsg) {
+			t.Errorf("got %q, want to contain %q", got, wantMsg)
+		}
+	}
+}
+
+func TestSpinnerRightAlignCountSmallWidth(t *testing.T) {
+	// A width smaller than the counter text used to panic with a negative
+	// strings.Repeat count inside the spinner goroutine. It should fall back
+	// to the unaligned layout instead.
+	out := &syncBuffer{}
+	s := spinner.New(
+		spinner.WithInterval(10*time.Millisecond),
+		spinner.WithWriter(out),
+		spinner.WithStartMessage("doing work"),
+		spinner.WithCount(20),
+		spinner.WithRightAlignCount(5),
+	)
+	s.Start()
+	time.Sleep(15 * time.Millisecond)
+	s.Stop()
+
+	// wait a bit because the spinner still has to erase before stopping
+	time.Sleep(25 * time.Millisecond)
+	got := out.String()
+
+	want := "⠋ doing work (0/20)"
+	if !strings.Contains(got, want) {
+		t.Errorf("got %q, want to contain %q", got, want)
+	}
+}
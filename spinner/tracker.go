@@ -34,6 +34,8 @@ func NewTracker(opts TrackerOptions) progress.Tracker {
 	var h slog.Handler
 	if opts.NewHandler != nil {
 		h = opts.NewHandler(wv)
+	} else if opts.UsePrettyHandler {
+		h = logutil.NewPrettyHandler(wv, nil)
 	} else {
 		h = slog.NewTextHandler(wv, nil)
 	}
@@ -64,6 +66,11 @@ type TrackerOptions struct {
 	// NewHandler is a function that creates a new slog.Handler to use for logging.
 	// If nil a slog.TextHandler will be created with default options.
 	NewHandler func(w io.Writer) slog.Handler
+	// UsePrettyHandler makes the tracker log using a logutil.PrettyHandler with
+	// default options instead of a slog.TextHandler, giving pretty colored output
+	// without having to write a NewHandler factory. Use NewHandler instead if the
+	// PrettyHandler needs to be customized; this field is ignored if NewHandler is set.
+	UsePrettyHandler bool
 	// DisableSpinner disables usage of a spinner and simply logs spinner messages.
 	// This is useful if you wish to dynamically control spinner behaviour based on
 	// an environment variable or command line flag.
@@ -8,6 +8,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/cszatmary/goutils/color"
 	"github.com/cszatmary/goutils/logutil"
 	"github.com/cszatmary/goutils/spinner"
 )
@@ -83,3 +84,22 @@ level=INFO msg="cleaning up"
 		t.Errorf("\ngot logs\n\t%s\nwant\n\t%s", got, want)
 	}
 }
+
+func TestTrackerPrettyHandler(t *testing.T) {
+	var b bytes.Buffer
+	tracker := spinner.NewTracker(spinner.TrackerOptions{
+		Writer:           &b,
+		UsePrettyHandler: true,
+	})
+	tracker.Info("hello world", "a", "one")
+
+	got := color.Strip(b.String())
+	// The PrettyHandler pads the message into a column, unlike slog.TextHandler
+	// which would produce msg="hello world".
+	if !strings.Contains(got, "INFO  hello world") {
+		t.Errorf("got %q, want PrettyHandler level and message formatting", got)
+	}
+	if !strings.Contains(got, "a=one") {
+		t.Errorf("got %q, want it to contain %q", got, "a=one")
+	}
+}
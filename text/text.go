@@ -3,9 +3,248 @@
 package text
 
 import (
+	"bytes"
+	"fmt"
+	"strconv"
 	"strings"
+	"unicode"
 )
 
+// Pluralize returns singular if n is 1, otherwise plural.
+// If plural is empty, it defaults to singular with an "s" appended.
+func Pluralize(n int, singular, plural string) string {
+	if n == 1 {
+		return singular
+	}
+	if plural == "" {
+		return singular + "s"
+	}
+	return plural
+}
+
+// Count returns n followed by the correctly pluralized form of singular,
+// e.g. "1 file", "3 files". See Pluralize for how the plural form is chosen.
+func Count(n int, singular, plural string) string {
+	return strconv.Itoa(n) + " " + Pluralize(n, singular, plural)
+}
+
+// LineDiff returns a simple line-by-line diff of want and got that is suited
+// for embedding in test failure messages. Lines only present in want are
+// prefixed with "-", lines only present in got are prefixed with "+", and
+// common lines are prefixed with a space.
+//
+// Lines are compared by position, it is not a minimal diff.
+func LineDiff(want, got string) string {
+	wantLines := strings.Split(want, "\n")
+	gotLines := strings.Split(got, "\n")
+	var sb strings.Builder
+	for i := 0; i < max(len(wantLines), len(gotLines)); i++ {
+		switch {
+		case i >= len(wantLines):
+			sb.WriteString("+ " + gotLines[i] + "\n")
+		case i >= len(gotLines):
+			sb.WriteString("- " + wantLines[i] + "\n")
+		case wantLines[i] == gotLines[i]:
+			sb.WriteString("  " + wantLines[i] + "\n")
+		default:
+			sb.WriteString("- " + wantLines[i] + "\n")
+			sb.WriteString("+ " + gotLines[i] + "\n")
+		}
+	}
+	return sb.String()
+}
+
+// SplitLines splits s into its lines. Lines are separated by '\n', and a
+// single trailing '\r' is trimmed from each line so both LF and CRLF line
+// endings are handled. A trailing newline does not produce an empty final
+// element, i.e. "a\nb\n" yields ["a", "b"].
+//
+// An empty string yields nil.
+func SplitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	// Drop a single trailing newline so it doesn't produce an empty final line.
+	if s[len(s)-1] == '\n' {
+		s = s[:len(s)-1]
+		if s != "" && s[len(s)-1] == '\r' {
+			s = s[:len(s)-1]
+		}
+	}
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if line != "" && line[len(line)-1] == '\r' {
+			lines[i] = line[:len(line)-1]
+		}
+	}
+	return lines
+}
+
+// NormalizeNewlines converts all line endings in s to '\n'.
+// Both CRLF ("\r\n") and lone CR ("\r") are converted, so text authored on
+// any platform can be reliably processed line by line.
+// If s contains no CR characters it is returned as is.
+func NormalizeNewlines(s string) string {
+	if !strings.ContainsRune(s, '\r') {
+		return s
+	}
+	return string(NormalizeNewlinesBytes([]byte(s)))
+}
+
+// NormalizeNewlinesBytes is like NormalizeNewlines but operates on a byte slice.
+// If b contains CR characters, a modified copy is returned.
+func NormalizeNewlinesBytes(b []byte) []byte {
+	if !bytes.ContainsRune(b, '\r') {
+		return b
+	}
+	out := make([]byte, 0, len(b))
+	for i := 0; i < len(b); i++ {
+		if b[i] == '\r' {
+			out = append(out, '\n')
+			// Skip the LF of a CRLF pair so it isn't doubled.
+			if i+1 < len(b) && b[i+1] == '\n' {
+				i++
+			}
+			continue
+		}
+		out = append(out, b[i])
+	}
+	return out
+}
+
+// SplitArgs splits s into arguments the way a shell would, i.e. on whitespace
+// while honouring single quotes, double quotes, and backslash escapes.
+// This is useful for splitting a command read from a config file into args,
+// which strings.Fields cannot do since it does not understand quoting.
+//
+//	SplitArgs(`git commit -m "hello world"`) // ["git", "commit", "-m", "hello world"]
+//
+// Inside single quotes every character is literal. Inside double quotes a
+// backslash escapes the next character. An error is returned for an
+// unterminated quote or a trailing backslash.
+func SplitArgs(s string) ([]string, error) {
+	var args []string
+	var sb strings.Builder
+	// inArg reports whether an argument is in progress. It is needed in
+	// addition to sb since a quoted empty string is a valid empty argument.
+	inArg := false
+	var quote rune // current quote char, 0 if not inside quotes
+	escaped := false
+	for _, r := range s {
+		switch {
+		case escaped:
+			sb.WriteRune(r)
+			escaped = false
+			inArg = true
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				sb.WriteRune(r)
+			}
+		case quote == '"':
+			switch r {
+			case '"':
+				quote = 0
+			case '\\':
+				escaped = true
+			default:
+				sb.WriteRune(r)
+			}
+		case r == '\\':
+			escaped = true
+			inArg = true
+		case r == '\'' || r == '"':
+			quote = r
+			inArg = true
+		case unicode.IsSpace(r):
+			if inArg {
+				args = append(args, sb.String())
+				sb.Reset()
+				inArg = false
+			}
+		default:
+			sb.WriteRune(r)
+			inArg = true
+		}
+	}
+	if escaped {
+		return nil, fmt.Errorf("text: trailing backslash in %q", s)
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("text: unterminated %c quote in %q", quote, s)
+	}
+	if inArg {
+		args = append(args, sb.String())
+	}
+	return args, nil
+}
+
+// DisplayWidth returns the number of terminal cells required to display s.
+// ANSI escape sequences are excluded, East Asian wide runes (e.g. CJK) count
+// as 2 cells, and combining characters count as 0.
+//
+// This is useful for terminal layout such as column alignment or knowing
+// how many characters to erase.
+func DisplayWidth(s string) int {
+	width := 0
+	inEscape := false
+	prevEscape := false
+	for _, r := range s {
+		if inEscape {
+			// The '[' immediately following the escape starts a CSI sequence,
+			// which is terminated by a byte in the range 0x40-0x7e.
+			if !(prevEscape && r == '[') && r >= 0x40 && r <= 0x7e {
+				inEscape = false
+			}
+			prevEscape = false
+			continue
+		}
+		if r == '\x1b' {
+			inEscape = true
+			prevEscape = true
+			continue
+		}
+		width += runeWidth(r)
+	}
+	return width
+}
+
+// runeWidth returns the number of terminal cells required to display r.
+// The wide rune table is intentionally minimal, it covers the common
+// East Asian ranges rather than the full Unicode East Asian Width property.
+func runeWidth(r rune) int {
+	switch {
+	case r == 0x200b: // zero width space
+		return 0
+	case r >= 0x0300 && r <= 0x036f: // combining diacritical marks
+		return 0
+	case r >= 0x1100 && r <= 0x115f: // Hangul Jamo
+		return 2
+	case r >= 0x2e80 && r <= 0x303e: // CJK radicals, Kangxi radicals, CJK symbols and punctuation
+		return 2
+	case r >= 0x3041 && r <= 0x33ff: // Hiragana, Katakana, CJK compatibility
+		return 2
+	case r >= 0x3400 && r <= 0x4dbf: // CJK unified ideographs extension A
+		return 2
+	case r >= 0x4e00 && r <= 0x9fff: // CJK unified ideographs
+		return 2
+	case r >= 0xac00 && r <= 0xd7a3: // Hangul syllables
+		return 2
+	case r >= 0xf900 && r <= 0xfaff: // CJK compatibility ideographs
+		return 2
+	case r >= 0xff00 && r <= 0xff60: // fullwidth forms
+		return 2
+	case r >= 0xffe0 && r <= 0xffe6: // fullwidth signs
+		return 2
+	case r >= 0x20000 && r <= 0x2fffd: // CJK unified ideographs extensions B-F
+		return 2
+	case r >= 0x30000 && r <= 0x3fffd: // CJK unified ideographs extension G
+		return 2
+	}
+	return 1
+}
+
 // ExpandVariables replaces ${var} in the byte slice based on the mapping function.
 // The returned byte slice is a copy of src with the replacements made, src is not modified.
 // If src contains no variables, src is returned as is.
@@ -82,6 +82,94 @@ func TestVariableMapper(t *testing.T) {
 	}
 }
 
+func TestPluralize(t *testing.T) {
+	tests := []struct {
+		name     string
+		n        int
+		singular string
+		plural   string
+		want     string
+	}{
+		{"zero", 0, "file", "", "files"},
+		{"one", 1, "file", "", "file"},
+		{"many", 3, "file", "", "files"},
+		{"explicit plural", 2, "directory", "directories", "directories"},
+		{"explicit plural singular", 1, "directory", "directories", "directory"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := text.Pluralize(tt.n, tt.singular, tt.plural)
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCount(t *testing.T) {
+	tests := []struct {
+		name     string
+		n        int
+		singular string
+		plural   string
+		want     string
+	}{
+		{"zero", 0, "file", "", "0 files"},
+		{"one", 1, "file", "", "1 file"},
+		{"many", 3, "file", "", "3 files"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := text.Count(tt.n, tt.singular, tt.plural)
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLineDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+		got  string
+		diff string
+	}{
+		{
+			"identical",
+			"a\nb",
+			"a\nb",
+			"  a\n  b\n",
+		},
+		{
+			"changed line",
+			"a\nb\nc",
+			"a\nx\nc",
+			"  a\n- b\n+ x\n  c\n",
+		},
+		{
+			"extra lines in got",
+			"a",
+			"a\nb",
+			"  a\n+ b\n",
+		},
+		{
+			"missing lines in got",
+			"a\nb",
+			"a",
+			"  a\n- b\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := text.LineDiff(tt.want, tt.got)
+			if got != tt.diff {
+				t.Errorf("got diff\n%s\nwant\n%s", got, tt.diff)
+			}
+		})
+	}
+}
+
 func BenchmarkExpandVariables(b *testing.B) {
 	b.Run("no-op", func(b *testing.B) {
 		for i := 0; i < b.N; i++ {
@@ -107,3 +195,167 @@ func BenchmarkExpandVariablesString(b *testing.B) {
 		}
 	})
 }
+
+func TestDisplayWidth(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		want int
+	}{
+		{"empty", "", 0},
+		{"ascii", "hello", 5},
+		{"ansi only", "\x1b[36m\x1b[0m", 0},
+		{"ansi wrapped text", "\x1b[36mhello\x1b[0m", 5},
+		{"cjk", "日本語", 6},
+		{"ansi wrapped cjk", "\x1b[1;31m日本語\x1b[0m", 6},
+		{"mixed", "go 言語!", 8},
+		{"hangul", "한국", 4},
+		{"combining characters", "été", 3},
+		{"fullwidth forms", "ＡＢ", 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := text.DisplayWidth(tt.s); got != tt.want {
+				t.Errorf("got width %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitLines(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		want []string
+	}{
+		{"empty", "", nil},
+		{"single line no newline", "a", []string{"a"}},
+		{"lf", "a\nb\nc", []string{"a", "b", "c"}},
+		{"lf trailing newline", "a\nb\n", []string{"a", "b"}},
+		{"crlf", "a\r\nb\r\nc", []string{"a", "b", "c"}},
+		{"crlf trailing newline", "a\r\nb\r\n", []string{"a", "b"}},
+		{"mixed", "a\r\nb\nc\r\n", []string{"a", "b", "c"}},
+		{"empty lines preserved", "a\n\nb", []string{"a", "", "b"}},
+		{"only newline", "\n", []string{""}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := text.SplitLines(tt.s)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "simple",
+			s:    "git status",
+			want: []string{"git", "status"},
+		},
+		{
+			name: "double quotes",
+			s:    `git commit -m "hello world"`,
+			want: []string{"git", "commit", "-m", "hello world"},
+		},
+		{
+			name: "single quotes",
+			s:    `echo 'it is "quoted"'`,
+			want: []string{"echo", `it is "quoted"`},
+		},
+		{
+			name: "escaped space",
+			s:    `cat foo\ bar.txt`,
+			want: []string{"cat", "foo bar.txt"},
+		},
+		{
+			name: "escape inside double quotes",
+			s:    `echo "a \"b\" c"`,
+			want: []string{"echo", `a "b" c`},
+		},
+		{
+			name: "quoted empty string",
+			s:    `run "" next`,
+			want: []string{"run", "", "next"},
+		},
+		{
+			name: "adjacent quotes join",
+			s:    `echo foo"bar baz"`,
+			want: []string{"echo", "foobar baz"},
+		},
+		{
+			name: "extra whitespace",
+			s:    "  a \t b  ",
+			want: []string{"a", "b"},
+		},
+		{
+			name: "empty",
+			s:    "",
+			want: nil,
+		},
+		{
+			name:    "unterminated double quote",
+			s:       `echo "oops`,
+			wantErr: true,
+		},
+		{
+			name:    "unterminated single quote",
+			s:       `echo 'oops`,
+			wantErr: true,
+		},
+		{
+			name:    "trailing backslash",
+			s:       `echo oops\`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := text.SplitArgs(tt.s)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("want error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("want nil error, got %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeNewlines(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"empty", "", ""},
+		{"already normalized", "a\nb\nc\n", "a\nb\nc\n"},
+		{"crlf", "a\r\nb\r\nc\r\n", "a\nb\nc\n"},
+		{"lone cr", "a\rb\rc", "a\nb\nc"},
+		{"mixed", "a\r\nb\rc\nd", "a\nb\nc\nd"},
+		{"cr before crlf", "a\r\r\nb", "a\n\nb"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := text.NormalizeNewlines(tt.in); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+			if got := string(text.NormalizeNewlinesBytes([]byte(tt.in))); got != tt.want {
+				t.Errorf("bytes: got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}